@@ -0,0 +1,144 @@
+// Package agent defines the interface between a game and the players in it, and
+// contains some baseline computer players.
+package agent
+
+import (
+	"github.com/nik0sc/mj"
+)
+
+const (
+	// Start is sent to each player when the hands are dealt. Seat is the player's own
+	// seat and Hand is their starting hand.
+	Start EventKind = iota + 1
+	// Draw is sent when a seat draws a tile. Tile is only set for the player who drew it.
+	Draw
+	// Discard is sent when a seat discards Tile.
+	Discard
+	// Meld is sent when a seat declares Meld. If it was claimed from a discard, Tile is
+	// the claimed tile and From is the seat that discarded it. Otherwise, Tile is the
+	// zero Tile.
+	Meld
+	// Bonus is sent when a seat sets aside a bonus tile.
+	Bonus
+	// Win is sent when a seat wins on Tile. From is the seat that discarded it, or the
+	// winner's own seat for a self-drawn win.
+	Win
+	// Exhausted is sent when the wall runs out with no winner.
+	Exhausted
+)
+
+// EventKind is the kind of an Event.
+type EventKind byte
+
+var eventNames = map[EventKind]string{
+	Start:     "start",
+	Draw:      "draw",
+	Discard:   "discard",
+	Meld:      "meld",
+	Bonus:     "bonus",
+	Win:       "win",
+	Exhausted: "exhausted",
+}
+
+func (k EventKind) String() string {
+	if s, ok := eventNames[k]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+// Event is something that happens at the table. Only the fields described by its Kind
+// are set.
+type Event struct {
+	Kind EventKind
+	Seat int
+	Tile mj.Tile
+	From int
+	Meld mj.Meld
+	Hand mj.Hand
+}
+
+// View is what a player knows about their own hand when making a decision.
+type View struct {
+	Seat  int
+	Rules mj.Ruleset
+	// Hand holds the concealed tiles, in sorted order.
+	Hand mj.Hand
+	// Melds holds the melds declared by the player.
+	Melds []mj.Meld
+}
+
+// Claim is a decision on a discarded tile, or on a tile the player drew. The zero Claim
+// passes.
+type Claim struct {
+	// Win is true to win on the tile.
+	Win bool
+	// Meld is the meld to form with the tile, if not winning.
+	Meld mj.Meld
+}
+
+// Pass reports whether the Claim passes on the tile.
+func (c Claim) Pass() bool {
+	return !c.Win && c.Meld.Kind == 0
+}
+
+// Agent is a player in a game. An Agent is only used by one game at a time, and its
+// methods are never called concurrently.
+type Agent interface {
+	// Observe is called for every event at the table, in order, including the player's
+	// own draws and discards.
+	Observe(e Event)
+	// Discard chooses a tile to discard from a hand with one more tile than a waiting
+	// hand. The tile must be in the hand.
+	Discard(v View) mj.Tile
+	// Claim chooses one of the options for a tile, or the zero Claim to pass. It is
+	// called when another seat discards a tile that the player can claim, and when the
+	// player draws a tile that they can win on, in which case the Event is the Draw.
+	Claim(v View, e Event, options []Claim) Claim
+}
+
+// Tracker records the tiles that a player has seen. Embed it in an Agent to implement
+// Observe.
+type Tracker struct {
+	// Seat is the player's own seat, as given by the Start event.
+	Seat int
+	// Visible holds every tile the player has seen.
+	Visible mj.Visible
+}
+
+// Observe records the tiles shown by the event.
+func (t *Tracker) Observe(e Event) {
+	switch e.Kind {
+	case Start:
+		t.Seat = e.Seat
+		t.Visible = mj.Visible{Rules: t.Visible.Rules}
+	case Discard:
+		t.Visible.Discard(e.Tile)
+	case Meld:
+		t.Visible.Meld(e.Meld, e.Tile)
+	case Bonus:
+		t.Visible.Bonus(e.Tile)
+	}
+}
+
+// Unseen returns the tiles that the player has not seen, given their own hand. The
+// player's melds were already seen in Meld events.
+func (t *Tracker) Unseen(v View) mj.Counter {
+	t.Visible.Rules = v.Rules
+	t.Visible.SetHand(v.Hand)
+	return t.Visible.Remaining()
+}
+
+// after returns the concealed tiles left after making the claim on the tile.
+func after(hand mj.Hand, c Claim, t mj.Tile) mj.Hand {
+	rest := hand.Append(t)
+	for _, mt := range c.Meld.Tiles() {
+		for i := range rest {
+			if rest[i] == mt {
+				rest = rest.Remove(i)
+				break
+			}
+		}
+	}
+	return rest
+}
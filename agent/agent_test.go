@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func tile(s string) mj.Tile {
+	return mj.MustParseHand(s)[0]
+}
+
+func TestAgents_Discard(t *testing.T) {
+	v := View{Rules: mj.HongKong, Hand: mj.MustParseHand("b1 b2 b3 b4 b5 c4 c5 c6 w7 w8 w9 hz hz hb")}
+	for _, a := range []Agent{&Greedy{}, &Efficiency{}} {
+		if got := a.Discard(v); got != tile("hb") {
+			t.Errorf("%T discarded %s, want hb", a, got)
+		}
+	}
+}
+
+func TestEfficiency_Wait(t *testing.T) {
+	// discarding b5 waits on hz or b7, and discarding b7 waits on b6
+	v := View{Rules: mj.HongKong, Hand: mj.MustParseHand("b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b5 b7 b7")}
+	for _, c := range []struct {
+		seen string
+		want string
+	}{
+		{"b6 b6 b6", "b5"},
+		{"hz hz", "b7"},
+	} {
+		a := &Efficiency{}
+		a.Observe(Event{Kind: Start, Seat: 0})
+		for _, st := range mj.MustParseHand(c.seen) {
+			a.Observe(Event{Kind: Discard, Seat: 1, Tile: st})
+		}
+		if got := a.Discard(v); got != tile(c.want) {
+			t.Errorf("with %s seen, discarded %s, want %s", c.seen, got, c.want)
+		}
+	}
+}
+
+func TestAgents_Deterministic(t *testing.T) {
+	v := View{Rules: mj.HongKong, Hand: mj.MustParseHand("b1 b4 b7 c2 c5 c8 w3 w6 w9 he hs hw hn hz")}
+	for _, mk := range []func(seed int64) Agent{
+		func(seed int64) Agent { return &Random{Seed: seed} },
+		func(seed int64) Agent { return &Greedy{Seed: seed} },
+		func(seed int64) Agent { return &Efficiency{Seed: seed} },
+	} {
+		a, b := mk(7), mk(7)
+		for i := 0; i < 5; i++ {
+			if ta, tb := a.Discard(v), b.Discard(v); ta != tb {
+				t.Errorf("%T: discard %d differs with the same seed: %s and %s", a, i, ta, tb)
+			}
+		}
+	}
+}
+
+func TestAgents_Claim(t *testing.T) {
+	v := View{Rules: mj.HongKong, Hand: mj.MustParseHand("b1 b2 b3 c4 c5 c6 w7 w8 hz hz hb hb he")}
+	e := Event{Kind: Discard, Seat: 3, Tile: tile("hb")}
+	peng := Claim{Meld: mj.Meld{Kind: mj.Peng, Tile: tile("hb")}}
+
+	for _, a := range []Agent{&Random{}, &Greedy{}, &Efficiency{}} {
+		if got := a.Claim(v, e, []Claim{peng, {Win: true}}); !got.Win {
+			t.Errorf("%T did not win: %+v", a, got)
+		}
+	}
+	for _, a := range []Agent{&Greedy{}, &Efficiency{}} {
+		if got := a.Claim(v, e, []Claim{peng}); got != peng {
+			t.Errorf("%T did not claim the peng: %+v", a, got)
+		}
+	}
+
+	// a chi that breaks a complete chi does not help
+	e.Tile = tile("b4")
+	chi := Claim{Meld: mj.Meld{Kind: mj.Chi, Tile: tile("b2")}}
+	if got := (&Greedy{}).Claim(v, e, []Claim{chi}); !got.Pass() {
+		t.Errorf("Greedy claimed %+v", got)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	var tr Tracker
+	tr.Observe(Event{Kind: Start, Seat: 1})
+	tr.Observe(Event{Kind: Discard, Seat: 0, Tile: tile("hb")})
+	tr.Observe(Event{Kind: Meld, Seat: 2, Tile: tile("hb"), From: 0, Meld: mj.Meld{Kind: mj.Peng, Tile: tile("hb")}})
+	tr.Observe(Event{Kind: Bonus, Seat: 3, Tile: tile("f1")})
+
+	unseen := tr.Unseen(View{Rules: mj.HongKong, Hand: mj.MustParseHand("hb b1")})
+	if got := unseen.Get(tile("hb")); got != 0 {
+		t.Errorf("unseen hb = %d, want 0", got)
+	}
+	if got := unseen.Get(tile("b1")); got != 3 {
+		t.Errorf("unseen b1 = %d, want 3", got)
+	}
+	if got := unseen.Get(tile("f1")); got != 0 {
+		t.Errorf("unseen f1 = %d, want 0", got)
+	}
+}
@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"math/rand"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/ukeire"
+	"github.com/nik0sc/mj/wait"
+)
+
+// Efficiency discards the tile that leaves the most unseen accepted tiles, as ranked by
+// ukeire.Discards, choosing at random between equally good tiles. Once its hand can be
+// left waiting, it keeps the wait with the most unseen winning tiles, found with
+// wait.Classify. It claims like Greedy.
+// The zero value is ready to use, and is seeded with 0.
+type Efficiency struct {
+	Tracker
+	// Seed seeds the choice between equally good tiles.
+	Seed int64
+
+	rng *rand.Rand
+}
+
+// Discard implements Agent.
+func (a *Efficiency) Discard(v View) mj.Tile {
+	a.Visible.Rules = v.Rules
+	ds := ukeire.Discards(v.Hand, a.Visible.Public(), v.Rules)
+	if ds[0].Shanten == 0 {
+		return a.bestWait(v, ds)
+	}
+	n := 1
+	for n < len(ds) && ds[n].Shanten == ds[0].Shanten && ds[n].Count == ds[0].Count &&
+		len(ds[n].Tiles) == len(ds[0].Tiles) {
+		n++
+	}
+	return ds[a.rand().Intn(n)].Tile
+}
+
+// bestWait chooses between the discards that leave the hand waiting.
+func (a *Efficiency) bestWait(v View, ds []ukeire.Discard) mj.Tile {
+	a.Visible.SetHand(v.Hand)
+	var best []mj.Tile
+	bestLive := -1
+	for _, d := range ds {
+		if d.Shanten != 0 {
+			break
+		}
+		left := v.Hand
+		for i, t := range left {
+			if t == d.Tile {
+				left = left.Remove(i)
+				break
+			}
+		}
+		var waits []mj.Tile
+		for _, w := range wait.Classify(left, v.Rules) {
+			waits = append(waits, w.Tile)
+		}
+		live := d.Count
+		if len(waits) > 0 {
+			// special hands are not classified, so they keep their acceptance
+			live = a.Visible.Live(waits)
+		}
+		switch {
+		case live > bestLive:
+			best, bestLive = []mj.Tile{d.Tile}, live
+		case live == bestLive:
+			best = append(best, d.Tile)
+		}
+	}
+	return best[a.rand().Intn(len(best))]
+}
+
+// Claim implements Agent.
+func (a *Efficiency) Claim(v View, e Event, options []Claim) Claim {
+	return claimIfBetter(v, e, options)
+}
+
+func (a *Efficiency) rand() *rand.Rand {
+	if a.rng == nil {
+		a.rng = rand.New(rand.NewSource(a.Seed))
+	}
+	return a.rng
+}
@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"math/rand"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+)
+
+// Greedy discards a tile that leaves the lowest shanten, choosing at random between
+// equally good tiles. It claims a meld if that lowers its shanten, and always wins when
+// it can. The zero value is ready to use, and is seeded with 0.
+type Greedy struct {
+	Tracker
+	// Seed seeds the choice between equally good tiles.
+	Seed int64
+
+	rng *rand.Rand
+}
+
+// Discard implements Agent.
+func (g *Greedy) Discard(v View) mj.Tile {
+	var best mj.Hand
+	bestShanten := 0
+	for i, t := range v.Hand {
+		if i > 0 && v.Hand[i-1] == t {
+			continue
+		}
+		s := handcheck.Shanten(v.Hand.Remove(i), v.Rules)
+		switch {
+		case len(best) == 0 || s < bestShanten:
+			best, bestShanten = mj.Hand{t}, s
+		case s == bestShanten:
+			best = append(best, t)
+		}
+	}
+	return best[g.rand().Intn(len(best))]
+}
+
+// Claim implements Agent.
+func (g *Greedy) Claim(v View, e Event, options []Claim) Claim {
+	return claimIfBetter(v, e, options)
+}
+
+func (g *Greedy) rand() *rand.Rand {
+	if g.rng == nil {
+		g.rng = rand.New(rand.NewSource(g.Seed))
+	}
+	return g.rng
+}
+
+// claimIfBetter wins if it can, or else takes the first meld that lowers the shanten of
+// the hand. A gang is taken as long as the shanten is no worse.
+func claimIfBetter(v View, e Event, options []Claim) Claim {
+	if c, ok := winOption(options); ok {
+		return c
+	}
+	before := handcheck.Shanten(v.Hand, v.Rules)
+	for _, c := range options {
+		rest := after(v.Hand, c, e.Tile)
+		if c.Meld.Kind == mj.Gang {
+			if handcheck.Shanten(rest, v.Rules) <= before {
+				return c
+			}
+			continue
+		}
+		for i := range rest {
+			if handcheck.Shanten(rest.Remove(i), v.Rules) < before {
+				return c
+			}
+		}
+	}
+	return Claim{}
+}
@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"math/rand"
+
+	"github.com/nik0sc/mj"
+)
+
+// Random discards a random tile and makes random claims, but always wins when it can.
+// The zero value is ready to use, and is seeded with 0.
+type Random struct {
+	Tracker
+	// Seed seeds the agent's choices, so that games with the same seeds are the same.
+	Seed int64
+
+	rng *rand.Rand
+}
+
+// Discard implements Agent.
+func (r *Random) Discard(v View) mj.Tile {
+	return v.Hand[r.rand().Intn(len(v.Hand))]
+}
+
+// Claim implements Agent.
+func (r *Random) Claim(v View, e Event, options []Claim) Claim {
+	if c, ok := winOption(options); ok {
+		return c
+	}
+	i := r.rand().Intn(len(options) + 1)
+	if i == len(options) {
+		return Claim{}
+	}
+	return options[i]
+}
+
+func (r *Random) rand() *rand.Rand {
+	if r.rng == nil {
+		r.rng = rand.New(rand.NewSource(r.Seed))
+	}
+	return r.rng
+}
+
+// winOption returns the option to win, if there is one.
+func winOption(options []Claim) (Claim, bool) {
+	for _, c := range options {
+		if c.Win {
+			return c, true
+		}
+	}
+	return Claim{}, false
+}
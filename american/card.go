@@ -0,0 +1,144 @@
+package american
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// HandSize is the number of tiles in a complete American hand.
+const HandSize = 14
+
+// Card is a list of patterns, usually one year's card.
+type Card struct {
+	Patterns []Pattern
+}
+
+// Pattern is one winning hand on a Card.
+type Pattern struct {
+	// Section is the family of hands that the pattern belongs to.
+	Section string
+	// Points is the value of the pattern.
+	Points int
+	// Concealed is true if the hand must be concealed.
+	Concealed bool
+	// Groups holds the groups as written on the card.
+	Groups []string
+	// Line is the line number in the card file, starting from 1.
+	Line int
+
+	sets []set
+}
+
+// set is a run of one symbol in a pattern.
+type set struct {
+	sym  byte
+	suit byte
+	n    int
+}
+
+// String returns the pattern as written on the card.
+func (p Pattern) String() string {
+	return p.Section + " " + strings.Join(p.Groups, " ")
+}
+
+// LoadCard reads a Card from a file.
+func LoadCard(path string) (Card, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Card{}, err
+	}
+	defer f.Close()
+	return ParseCard(f)
+}
+
+// ParseCard reads a Card in the format described in the package documentation.
+func ParseCard(r io.Reader) (Card, error) {
+	var c Card
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		s := strings.TrimSpace(sc.Text())
+		if s == "" || s[0] == '#' {
+			continue
+		}
+		p, err := ParsePattern(s)
+		if err != nil {
+			return Card{}, fmt.Errorf("line %d: %w", line, err)
+		}
+		p.Line = line
+		c.Patterns = append(c.Patterns, p)
+	}
+	if err := sc.Err(); err != nil {
+		return Card{}, err
+	}
+	return c, nil
+}
+
+// ParsePattern parses one line of a card.
+func ParsePattern(s string) (Pattern, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 4 {
+		return Pattern{}, errors.New("want section, points, X or C, and groups")
+	}
+
+	p := Pattern{Section: fields[0], Groups: fields[3:]}
+	var err error
+	p.Points, err = strconv.Atoi(fields[1])
+	if err != nil {
+		return Pattern{}, fmt.Errorf("invalid points: %w", err)
+	}
+	switch fields[2] {
+	case "X":
+	case "C":
+		p.Concealed = true
+	default:
+		return Pattern{}, fmt.Errorf("want X or C, got %q", fields[2])
+	}
+
+	n := 0
+	for _, g := range p.Groups {
+		sets, err := parseGroup(g)
+		if err != nil {
+			return Pattern{}, fmt.Errorf("group %q: %w", g, err)
+		}
+		for _, st := range sets {
+			n += st.n
+		}
+		p.sets = append(p.sets, sets...)
+	}
+	if n != HandSize {
+		return Pattern{}, fmt.Errorf("pattern has %d tiles, want %d", n, HandSize)
+	}
+	return p, nil
+}
+
+func parseGroup(g string) ([]set, error) {
+	suit := byte('a')
+	if last := g[len(g)-1]; 'a' <= last && last <= 'c' {
+		suit = last
+		g = g[:len(g)-1]
+	}
+	if g == "" {
+		return nil, errors.New("no tiles")
+	}
+
+	var sets []set
+	for i := 0; i < len(g); i++ {
+		sym := g[i]
+		if !strings.ContainsRune("123456789KLMD0NEWSF", rune(sym)) {
+			return nil, fmt.Errorf("unknown symbol %q", sym)
+		}
+		if len(sets) > 0 && sets[len(sets)-1].sym == sym {
+			sets[len(sets)-1].n++
+			continue
+		}
+		sets = append(sets, set{sym: sym, suit: suit, n: 1})
+	}
+	return sets, nil
+}
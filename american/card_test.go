@@ -0,0 +1,50 @@
+package american
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadCard(t *testing.T) {
+	c, err := LoadCard("testdata/sample.card")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Patterns) != 9 {
+		t.Fatalf("got %d patterns, want 9", len(c.Patterns))
+	}
+
+	p := c.Patterns[4]
+	if p.Section != "Consecutive" || p.Points != 30 || !p.Concealed || p.Line != 7 {
+		t.Errorf("wrong pattern: %+v", p)
+	}
+}
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantErr string
+	}{
+		{"ok", "2468 25 X 222a 4444a 666a 8888a", ""},
+		{"short", "2468 25 X 222a 4444a 666a 888a", "13 tiles"},
+		{"bad points", "2468 x X 222a 4444a 666a 8888a FF", "invalid points"},
+		{"bad exposure", "2468 25 Y 222a 4444a 666a 8888a FF", "X or C"},
+		{"bad symbol", "2468 25 X 222a 4444a 666a 8888a FZ", "unknown symbol"},
+		{"too few fields", "2468 25 X", "want section"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePattern(tt.s)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ParsePattern() err = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParsePattern() err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
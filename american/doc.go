@@ -0,0 +1,26 @@
+// Package american matches hands against the patterns of an American (NMJL-style) card.
+//
+// In American mahjong, a winning hand is one of the patterns printed on the year's
+// card, rather than any grouping of sets and a pair. A Card is read from a text file
+// with one pattern per line:
+//
+//  # section  points  X/C  groups...
+//  2468       25      X    FF 2222a 44b 66b 8888a
+//
+// Blank lines and lines starting with # are ignored. The section names the family the
+// pattern belongs to and must not contain spaces. X marks an exposed pattern and C a
+// concealed one. Each group is a run of tile symbols, optionally followed by a suit
+// variable a, b or c. Different suit variables in one pattern stand for different
+// suits. The symbols are:
+//  - 1 to 9: a number in the group's suit.
+//  - K, L and M: the variable numbers n, n+1 and n+2, in the group's suit.
+//  - D: the dragon of the group's suit: green for bamboo, red for wan and white
+//      for coin.
+//  - 0: the white dragon, used as a zero.
+//  - N, E, W and S: the winds.
+//  - F: any flower.
+//
+// Groups without a suit variable use suit a. A run of three or more of the same
+// symbol (a pung, kong or quint) may be completed with jokers; pairs and single
+// tiles may not.
+package american
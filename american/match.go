@@ -0,0 +1,207 @@
+package american
+
+import (
+	"sort"
+
+	"github.com/nik0sc/mj"
+)
+
+// AnyFlower stands for any flower in the tiles needed by a Match.
+var AnyFlower = mj.Tile{Suit: mj.Flower, Value: mj.FlowerBase}
+
+// Match is how close a hand is to one Pattern.
+type Match struct {
+	Pattern Pattern
+	// Distance is the number of tiles the hand still needs to complete the pattern.
+	// A complete hand has a Distance of 0.
+	Distance int
+	// Need holds the tiles still needed, in sorted order. Any flower is shown as
+	// AnyFlower.
+	Need mj.Hand
+	// Target is the complete hand that the hand is closest to, without jokers.
+	Target mj.Hand
+}
+
+// Match returns how close the hand is to each pattern on the card, closest first.
+// Patterns that are equally close are kept in card order.
+func (c Card) Match(h mj.Hand) []Match {
+	ms := make([]Match, len(c.Patterns))
+	for i, p := range c.Patterns {
+		ms[i] = p.Match(h)
+	}
+	sort.SliceStable(ms, func(i, j int) bool { return ms[i].Distance < ms[j].Distance })
+	return ms
+}
+
+// Match returns how close the hand is to the pattern. Every assignment of suits to the
+// suit variables and of numbers to K, L and M is tried, and the closest is returned.
+// The hand may have fewer than HandSize tiles. Jokers in the hand are used for missing
+// tiles in runs of three or more.
+func (p Pattern) Match(h mj.Hand) Match {
+	cnt := h.ToCount()
+	best := Match{Pattern: p, Distance: HandSize + 1}
+
+	p.assignments(func(suits map[byte]mj.Suit, k mj.Value) {
+		m := p.matchWith(cnt, suits, k)
+		if m.Distance < best.Distance {
+			best = m
+		}
+	})
+	return best
+}
+
+// assignments calls f with every assignment of suits to suit variables and numbers to K.
+func (p Pattern) assignments(f func(suits map[byte]mj.Suit, k mj.Value)) {
+	var vars []byte
+	maxOffset := -1
+	for _, st := range p.sets {
+		if st.suited() && !containsByte(vars, st.suit) {
+			vars = append(vars, st.suit)
+		}
+		if off := st.offset(); off > maxOffset {
+			maxOffset = off
+		}
+	}
+
+	ks := []mj.Value{0}
+	if maxOffset >= 0 {
+		ks = nil
+		for k := mj.Value(1); int(k)+maxOffset <= 9; k++ {
+			ks = append(ks, k)
+		}
+	}
+
+	basics := []mj.Suit{mj.Bamboo, mj.Coin, mj.Wan}
+	suits := make(map[byte]mj.Suit)
+	var assign func(i int)
+	assign = func(i int) {
+		if i == len(vars) {
+			for _, k := range ks {
+				f(suits, k)
+			}
+			return
+		}
+	next:
+		for _, s := range basics {
+			for _, v := range vars[:i] {
+				if suits[v] == s {
+					continue next
+				}
+			}
+			suits[vars[i]] = s
+			assign(i + 1)
+		}
+	}
+	assign(0)
+}
+
+func (p Pattern) matchWith(cnt mj.Counter, suits map[byte]mj.Suit, k mj.Value) Match {
+	have := cnt.Map()
+	flowers := 0
+	for t, n := range have {
+		if t.Suit == mj.Flower {
+			flowers += n
+		}
+	}
+	jokers := have[mj.JokerTile]
+
+	m := Match{Pattern: p}
+	var jokerable mj.Hand
+	for _, st := range p.sets {
+		t := st.tile(suits, k)
+		for i := 0; i < st.n; i++ {
+			m.Target = append(m.Target, t)
+		}
+
+		var take int
+		if st.sym == 'F' {
+			take = minInt(flowers, st.n)
+			flowers -= take
+		} else {
+			take = minInt(have[t], st.n)
+			have[t] -= take
+		}
+		for i := take; i < st.n; i++ {
+			if st.n >= 3 {
+				jokerable = append(jokerable, t)
+			} else {
+				m.Need = append(m.Need, t)
+			}
+		}
+	}
+
+	used := minInt(jokers, len(jokerable))
+	m.Need = append(m.Need, jokerable[used:]...)
+	sort.Sort(m.Need)
+	sort.Sort(m.Target)
+	m.Distance = len(m.Need)
+	return m
+}
+
+func (st set) suited() bool {
+	return ('1' <= st.sym && st.sym <= '9') || st.offset() >= 0 || st.sym == 'D'
+}
+
+// offset returns the offset from n of K, L or M, and -1 for other symbols.
+func (st set) offset() int {
+	switch st.sym {
+	case 'K':
+		return 0
+	case 'L':
+		return 1
+	case 'M':
+		return 2
+	}
+	return -1
+}
+
+func (st set) tile(suits map[byte]mj.Suit, k mj.Value) mj.Tile {
+	s := suits[st.suit]
+	switch {
+	case '1' <= st.sym && st.sym <= '9':
+		return mj.Tile{Suit: s, Value: mj.Value(st.sym - '0')}
+	case st.offset() >= 0:
+		return mj.Tile{Suit: s, Value: k + mj.Value(st.offset())}
+	}
+
+	switch st.sym {
+	case 'D':
+		return mj.Tile{Suit: mj.Honour, Value: dragons[s]}
+	case '0':
+		return mj.Tile{Suit: mj.Honour, Value: mj.Ban}
+	case 'N':
+		return mj.Tile{Suit: mj.Honour, Value: mj.North}
+	case 'E':
+		return mj.Tile{Suit: mj.Honour, Value: mj.East}
+	case 'W':
+		return mj.Tile{Suit: mj.Honour, Value: mj.West}
+	case 'S':
+		return mj.Tile{Suit: mj.Honour, Value: mj.South}
+	case 'F':
+		return AnyFlower
+	}
+	panic("unknown symbol: " + string(st.sym))
+}
+
+// dragons maps each suit to its dragon.
+var dragons = map[mj.Suit]mj.Value{
+	mj.Bamboo: mj.Fa,
+	mj.Wan:    mj.Zhong,
+	mj.Coin:   mj.Ban,
+}
+
+func containsByte(bs []byte, b byte) bool {
+	for _, x := range bs {
+		if x == b {
+			return true
+		}
+	}
+	return false
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,102 @@
+package american
+
+import (
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestPattern_Match(t *testing.T) {
+	tests := []struct {
+		name         string
+		pattern      string
+		hand         string
+		wantDistance int
+		wantNeed     string
+	}{
+		{
+			"complete",
+			"2468 25 X 222a 4444a 666a 8888a",
+			"c2 c2 c2 c4 c4 c4 c4 c6 c6 c6 c8 c8 c8 c8",
+			0,
+			"",
+		},
+		{
+			"jokers in kong",
+			"2468 25 X 222a 4444a 666a 8888a",
+			"c2 c2 c2 c4 c4 j1 j1 c6 c6 c6 c8 c8 c8 c8",
+			0,
+			"",
+		},
+		{
+			"no jokers in pair",
+			"2468 25 X FF 2222a 44b 66b 8888a",
+			"f1 j1 w2 w2 w2 w2 b4 b4 b6 j1 w8 w8 w8 w8",
+			2,
+			"b6 f1",
+		},
+		{
+			"like numbers",
+			"LikeNumbers 25 X FF KKKKa KKKKb KKKKc",
+			"f1 f2 b5 b5 b5 b5 c5 c5 c5 c5 w5 w5 w5 hz",
+			1,
+			"w5",
+		},
+		{
+			"consecutive picks the best run",
+			"Consecutive 25 X KKa LLLa MMMMa KKKb LLb",
+			"c4 c4 c5 c5 c5 c6 c6 c6 c6 w4 w4 w4 w5",
+			1,
+			"w5",
+		},
+		{
+			"dragon of the suit",
+			"Consecutive 30 C KKKa LLLa MMMa DDDa NN",
+			"b1 b1 b1 b2 b2 b2 b2 b3 b3 b3 hf hf hn hn",
+			1,
+			"hf",
+		},
+		{
+			"singles and pairs",
+			"SinglesPairs 50 C NN EW SS 11a 22b 33c 0D",
+			"hn hn he hw hs hs b1 b1 c2 c2 w3 w3 hb",
+			1,
+			"hf",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParsePattern(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := p.Match(mj.MustParseHand(tt.hand))
+			var want mj.Hand
+			if tt.wantNeed != "" {
+				want = mj.MustParseHand(tt.wantNeed)
+			}
+			if got.Distance != tt.wantDistance || got.Need.Marshal() != want.Marshal() {
+				t.Errorf("Match() = %d %v, want %d %v", got.Distance, got.Need, tt.wantDistance, want)
+			}
+			if len(got.Target) != HandSize {
+				t.Errorf("Match() target has %d tiles", len(got.Target))
+			}
+		})
+	}
+}
+
+func TestCard_Match(t *testing.T) {
+	c, err := LoadCard("testdata/sample.card")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms := c.Match(mj.MustParseHand("hn hn hn hn he he he hw hw hw hs hs hs j1"))
+	if ms[0].Pattern.Section != "WindsDragons" || ms[0].Distance != 0 {
+		t.Errorf("closest = %v, distance %d", ms[0].Pattern, ms[0].Distance)
+	}
+	for i := 1; i < len(ms); i++ {
+		if ms[i].Distance < ms[i-1].Distance {
+			t.Errorf("not sorted at %d", i)
+		}
+	}
+}
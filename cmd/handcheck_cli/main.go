@@ -8,6 +8,7 @@ import (
 
 	"github.com/nik0sc/mj"
 	"github.com/nik0sc/mj/handcheck"
+	"github.com/nik0sc/mj/ukeire"
 	"github.com/nik0sc/mj/wait"
 )
 
@@ -38,4 +39,18 @@ func main() {
 	} else {
 		fmt.Printf("waits: %s\n", mj.Hand(waits).String())
 	}
+
+	switch len(h) % 3 {
+	case 1:
+		a := ukeire.Accept(h, mj.Counter{}, mj.HongKong)
+		fmt.Printf("shanten: %d\n", a.Shanten)
+		fmt.Printf("accepts: %s (%d tiles)\n", a.Tiles.String(), a.Count)
+	case 2:
+		ds := ukeire.Discards(h, mj.Counter{}, mj.HongKong)
+		fmt.Printf("shanten: %d\n", handcheck.Shanten(h, mj.HongKong))
+		for _, d := range ds {
+			fmt.Printf("discard %s: shanten %d, accepts %s (%d tiles)\n",
+				mj.FormatTile(d.Tile), d.Shanten, d.Tiles.String(), d.Count)
+		}
+	}
 }
@@ -9,10 +9,14 @@ import (
 
 	"github.com/nik0sc/mj"
 	"github.com/nik0sc/mj/handcheck"
+	"github.com/nik0sc/mj/ukeire"
 )
 
 // window.optCheck(String, Function(String, String), Boolean, Boolean)
 var optCheck js.Func
+
+// window.analyse(String, Function(String, String))
+var analyse js.Func
 var stop chan struct{}
 
 func init() {
@@ -47,11 +51,41 @@ func init() {
 		}()
 		return nil
 	})
+
+	analyse = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		hand := args[0].String()
+		cb := args[1]
+
+		if cb.Type() != js.TypeFunction {
+			panic("pos arg 1 not function")
+		}
+
+		h, err := mj.ParseHand(hand)
+		if err != nil {
+			cb.Invoke(js.Null(), err.Error())
+			return nil
+		}
+		switch len(h) % 3 {
+		case 1:
+			a := ukeire.Accept(h, mj.Counter{}, mj.HongKong)
+			cb.Invoke(fmt.Sprintf("shanten %d, accepts %s (%d tiles)", a.Shanten, a.Tiles, a.Count), js.Null())
+		case 2:
+			d := ukeire.Discards(h, mj.Counter{}, mj.HongKong)[0]
+			cb.Invoke(fmt.Sprintf("shanten %d, discard %s, accepts %s (%d tiles)",
+				handcheck.Shanten(h, mj.HongKong), mj.FormatTile(d.Tile), d.Tiles, d.Count), js.Null())
+		default:
+			cb.Invoke(js.Null(), fmt.Sprintf("cannot analyse a hand of %d tiles", len(h)))
+		}
+		return nil
+	})
 }
 
 func main() {
 	js.Global().Get("window").Set("optCheck", optCheck)
+	js.Global().Get("window").Set("analyse", analyse)
 	<-stop
 	js.Global().Delete("optCheck")
+	js.Global().Delete("analyse")
 	optCheck.Release()
+	analyse.Release()
 }
@@ -0,0 +1,28 @@
+// Command mjaibot is a riichi bot that speaks the mjai protocol on stdin and stdout, for
+// harnesses that run bots as subprocesses. It plays for tile efficiency, as described for
+// mjai.Efficient. Nothing but protocol messages is written to stdout; errors go to stderr.
+//
+// Usage:
+//   mjaibot -name mj
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nik0sc/mj/mjai"
+)
+
+func main() {
+	name := flag.String("name", "mj", "name to join the game with")
+	room := flag.String("room", "default", "room to join")
+	flag.Parse()
+
+	// each answer is written to stdout in a single unbuffered write
+	b := &mjai.Bot{Name: *name, Room: *room}
+	if err := b.Run(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "mjaibot: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/record"
+	"github.com/nik0sc/mj/tenhou"
+)
+
+// decision is one discard made by a player, with what the player could see.
+type decision struct {
+	// label names the round and turn.
+	label string
+	rules mj.Ruleset
+	seat  int
+	// hand holds the concealed tiles, including the tile drawn or claimed.
+	hand    mj.Hand
+	discard mj.Tile
+	// discards, melds and riichi hold what each seat has shown before the discard.
+	discards []mj.Hand
+	melds    [][]mj.Meld
+	riichi   []bool
+	// dealtIn is the seat that won on the discard, or -1.
+	dealtIn int
+}
+
+// turnsFile is the simple per-turn format.
+type turnsFile struct {
+	Rules   string     `json:"rules"`
+	Players []string   `json:"players"`
+	Turns   []turnLine `json:"turns"`
+}
+
+type turnLine struct {
+	Seat     int        `json:"seat"`
+	Hand     string     `json:"hand"`
+	Discard  string     `json:"discard"`
+	Discards []string   `json:"discards"`
+	Melds    [][]string `json:"melds"`
+	Riichi   []bool     `json:"riichi"`
+	DealtIn  *int       `json:"dealt_in"`
+}
+
+// load reads a game in either format, and returns the seat's decisions and the names of
+// the players, if they are known.
+func load(b []byte, seat int) ([]decision, []string, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '{' && bytes.Contains(b, []byte(`"turns"`)) {
+		return loadTurns(b, seat)
+	}
+	l, err := tenhou.Read(bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	ds, err := fromTenhou(l, seat)
+	return ds, l.Players, err
+}
+
+func fromTenhou(l tenhou.Log, seat int) ([]decision, error) {
+	var out []decision
+	winds := "ESWN"
+	for i, rd := range l.Rounds {
+		turns, err := rd.Turns()
+		if err != nil {
+			return nil, fmt.Errorf("round %d: %w", i, err)
+		}
+		// a win on the last discard of the round was dealt in by the discarder; with more
+		// than one winner, the first is reported
+		dealtIn := -1
+		n := len(rd.Events)
+		for n > 0 && rd.Events[n-1].Kind == tenhou.Win {
+			n--
+		}
+		if n > 0 && n < len(rd.Events) && rd.Events[n-1].Kind == tenhou.Discard {
+			if w := rd.Events[n]; w.From != w.Seat {
+				dealtIn = w.Seat
+			}
+		}
+
+		name := fmt.Sprintf("%c%d", winds[rd.Round/len(rd.Hands)%4], rd.Round%len(rd.Hands)+1)
+		if rd.Honba > 0 {
+			name += fmt.Sprintf("-%d", rd.Honba)
+		}
+		mine := 0
+		for j, tn := range turns {
+			if tn.Seat != seat {
+				continue
+			}
+			mine++
+			d := decision{
+				label:    fmt.Sprintf("%s #%d", name, mine),
+				rules:    rd.Rules,
+				seat:     seat,
+				hand:     tn.Hand,
+				discard:  tn.Discard.Tile,
+				discards: tn.Discards,
+				melds:    tn.Melds,
+				riichi:   tn.Riichi,
+				dealtIn:  -1,
+			}
+			if j == len(turns)-1 {
+				d.dealtIn = dealtIn
+			}
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func loadTurns(b []byte, seat int) ([]decision, []string, error) {
+	var f turnsFile
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, nil, err
+	}
+	rs := mj.Riichi
+	if f.Rules != "" {
+		var ok bool
+		if rs, ok = preset(f.Rules); !ok {
+			return nil, nil, fmt.Errorf("unknown ruleset %q", f.Rules)
+		}
+	}
+
+	var out []decision
+	for i, tl := range f.Turns {
+		if tl.Seat != seat {
+			continue
+		}
+		d, err := tl.decision(rs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("turn %d: %w", i, err)
+		}
+		d.label = fmt.Sprintf("#%d", len(out)+1)
+		out = append(out, d)
+	}
+	return out, f.Players, nil
+}
+
+func (tl turnLine) decision(rs mj.Ruleset) (decision, error) {
+	n := rs.Players()
+	d := decision{
+		rules:    rs,
+		seat:     tl.Seat,
+		discards: make([]mj.Hand, n),
+		melds:    make([][]mj.Meld, n),
+		riichi:   make([]bool, n),
+		dealtIn:  -1,
+	}
+	var err error
+	if d.hand, err = mj.ParseHand(tl.Hand); err != nil {
+		return decision{}, fmt.Errorf("hand: %w", err)
+	}
+	if d.discard, err = mj.ParseTile(tl.Discard); err != nil {
+		return decision{}, fmt.Errorf("discard: %w", err)
+	}
+	if len(tl.Discards) > n || len(tl.Melds) > n || len(tl.Riichi) > n {
+		return decision{}, fmt.Errorf("more than %d seats", n)
+	}
+	for s, ds := range tl.Discards {
+		if strings.TrimSpace(ds) == "" {
+			continue
+		}
+		if d.discards[s], err = mj.ParseHand(ds); err != nil {
+			return decision{}, fmt.Errorf("discards of seat %d: %w", s, err)
+		}
+	}
+	for s, ms := range tl.Melds {
+		for _, m := range ms {
+			meld, err := record.ParseMeld(m)
+			if err != nil {
+				return decision{}, fmt.Errorf("melds of seat %d: %w", s, err)
+			}
+			d.melds[s] = append(d.melds[s], meld)
+		}
+	}
+	copy(d.riichi, tl.Riichi)
+	if tl.DealtIn != nil {
+		d.dealtIn = *tl.DealtIn
+	}
+	switch {
+	case tl.Seat < 0 || tl.Seat >= n:
+		return decision{}, fmt.Errorf("no seat %d", tl.Seat)
+	case d.dealtIn >= n || d.dealtIn == tl.Seat:
+		return decision{}, fmt.Errorf("seat %d cannot win on the discard", d.dealtIn)
+	case len(d.hand)%3 != 2:
+		return decision{}, fmt.Errorf("hand has %d tiles, want 3n+2", len(d.hand))
+	case d.hand.ToCount().Get(d.discard) == 0:
+		return decision{}, fmt.Errorf("discard %s is not in the hand", tl.Discard)
+	}
+	return d, nil
+}
+
+func preset(name string) (mj.Ruleset, bool) {
+	for _, rs := range mj.Presets() {
+		if strings.EqualFold(rs.Name, name) {
+			return rs, true
+		}
+	}
+	return mj.Ruleset{}, false
+}
@@ -0,0 +1,162 @@
+// Command review goes over one player's discards in a recorded game. For each discard,
+// it works out the best discard by tile efficiency with package ukeire, and flags the
+// discard if it left a higher shanten than the best, or gave up at least -loss unseen
+// tiles of acceptance. A discard that dealt into a player who had declared riichi, or
+// who had called every set but one, is flagged if the hand held a tile that was safe
+// against that player. Each discard is printed with its verdict, then a summary.
+//
+// The game is read from the named file, or stdin. It may be a Tenhou log, in the mjlog
+// XML or tenhou.net/6 JSON format, or a simple per-turn JSON file:
+//
+//   {
+//     "rules": "Riichi",
+//     "players": ["a", "b", "c", "d"],
+//     "turns": [
+//       {
+//         "seat": 0,
+//         "hand": "w1 w2 w3 c4 c5 c6 b7 b8 b9 he he hz hz hf",
+//         "discard": "hf",
+//         "discards": ["hn b1", "w9 hs", "c1", "b9 hz"],
+//         "melds": [[], [], ["peng hb"], []],
+//         "riichi": [false, true, false, false],
+//         "dealt_in": 1
+//       }
+//     ]
+//   }
+//
+// The rules name a preset and default to Riichi, and the players are optional. Each turn
+// holds the concealed tiles of the seat before it discards, including the tile it drew or
+// claimed. Its discards, melds and riichi are what each seat had shown before the discard,
+// and may be left out; melds are written as in package record. dealt_in is the seat that
+// won on the discard, if any.
+//
+// Usage:
+//   review -seat 0 game.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+
+	"github.com/nik0sc/mj"
+)
+
+func main() {
+	seat := flag.Int("seat", 0, "seat of the player to review")
+	loss := flag.Int("loss", 4, "unseen tiles of acceptance that a discard may give up before it is flagged")
+	flagged := flag.Bool("flagged", false, "only print the discards that are flagged")
+	flag.Parse()
+
+	var r io.Reader = os.Stdin
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fatalf("%v", err)
+		}
+		defer f.Close()
+		r = f
+	default:
+		fatalf("more than one game given")
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	ds, players, err := load(b, *seat)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if len(ds) == 0 {
+		fatalf("seat %d made no discards", *seat)
+	}
+
+	rs := make([]review, len(ds))
+	for i, d := range ds {
+		if rs[i], err = judge(d); err != nil {
+			fatalf("%v", err)
+		}
+	}
+	write(os.Stdout, rs, *loss, *flagged)
+
+	who := fmt.Sprintf("seat %d", *seat)
+	if *seat < len(players) && players[*seat] != "" {
+		who += " (" + players[*seat] + ")"
+	}
+	summarise(os.Stdout, who, rs, *loss)
+}
+
+// write prints each discard with the best discard next to it.
+func write(w io.Writer, rs []review, minLoss int, onlyFlagged bool) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "turn\thand\tdiscard\tshanten\tukeire\tbest\tshanten\tukeire\t\tnote")
+	for _, r := range rs {
+		f := r.flagged(minLoss)
+		if onlyFlagged && !f {
+			continue
+		}
+		mark := ""
+		if f {
+			mark = "!"
+		}
+		note := ""
+		for i, n := range r.notes {
+			if i > 0 {
+				note += "; "
+			}
+			note += n
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%s\t%d\t%d\t%s\t%s\n", r.label, mj.FormatHand(r.hand),
+			mj.FormatTile(r.discard), r.chosen.Shanten, r.chosen.Count,
+			mj.FormatTile(r.best.Tile), r.best.Shanten, r.best.Count, mark, note)
+	}
+	tw.Flush()
+}
+
+// summarise prints the totals over every discard.
+func summarise(w io.Writer, who string, rs []review, minLoss int) {
+	var judged, best, worse, losses, lost, dealIns, obvious int
+	for _, r := range rs {
+		if r.dealtIn >= 0 {
+			dealIns++
+		}
+		if r.obvious {
+			obvious++
+		}
+		if r.riichi {
+			continue
+		}
+		judged++
+		switch {
+		case r.chosen.Shanten > r.best.Shanten:
+			worse++
+		case r.loss == 0:
+			best++
+		case r.loss >= minLoss:
+			losses++
+			lost += r.loss
+		}
+	}
+	fmt.Fprintf(w, "\n%s: %d discards, %d after riichi\n", who, len(rs), len(rs)-judged)
+	fmt.Fprintf(w, "  as good as the best: %d (%.1f%%)\n", best, percent(best, judged))
+	fmt.Fprintf(w, "  raised shanten: %d\n", worse)
+	fmt.Fprintf(w, "  gave up %d+ tiles: %d (%d tiles in all)\n", minLoss, losses, lost)
+	fmt.Fprintf(w, "  dealt in: %d (%d into an obvious wait)\n", dealIns, obvious)
+}
+
+func percent(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(d)
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "review: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/danger"
+	"github.com/nik0sc/mj/ukeire"
+)
+
+// review is the verdict on one decision.
+type review struct {
+	decision
+	// chosen and best are the acceptance after the actual discard and after the best
+	// discard by tile efficiency.
+	chosen, best ukeire.Discard
+	// riichi is true if the player had already declared riichi, so the discard was forced
+	// and is not judged.
+	riichi bool
+	// loss is the number of unseen accepted tiles given up against the best discard, when
+	// both leave the same shanten.
+	loss int
+	// obvious is true if the discard dealt into a seat that was clearly waiting, while a
+	// safe tile was held.
+	obvious bool
+	notes   []string
+}
+
+// flagged reports whether the decision is a mistake.
+func (r review) flagged(minLoss int) bool {
+	return !r.riichi && (r.chosen.Shanten > r.best.Shanten || r.loss >= minLoss || r.obvious)
+}
+
+// judge reviews a decision. It returns an error if the discard is not in the hand.
+func judge(d decision) (review, error) {
+	r := review{decision: d, riichi: d.seat < len(d.riichi) && d.riichi[d.seat]}
+	visible := seen(d)
+	ds := ukeire.Discards(d.hand, visible, d.rules)
+	r.best = ds[0]
+	found := false
+	for _, c := range ds {
+		if c.Tile == d.discard {
+			r.chosen, found = c, true
+		}
+	}
+	if !found {
+		return review{}, fmt.Errorf("%s: discard %s is not in the hand %s",
+			d.label, mj.FormatTile(d.discard), mj.FormatHand(d.hand))
+	}
+	switch {
+	case r.riichi:
+		r.notes = append(r.notes, "in riichi")
+	case r.chosen.Shanten > r.best.Shanten:
+		r.notes = append(r.notes, fmt.Sprintf("shanten +%d", r.chosen.Shanten-r.best.Shanten))
+	case r.chosen.Count < r.best.Count:
+		r.loss = r.best.Count - r.chosen.Count
+		r.notes = append(r.notes, fmt.Sprintf("%d fewer tiles", r.loss))
+	}
+
+	if w := d.dealtIn; w >= 0 && w < len(d.discards) {
+		note := fmt.Sprintf("dealt in to seat %d", w)
+		if waiting(d, w) {
+			note += " " + waitingReason(d, w)
+			if safe, ok := safeTile(d, w, visible); ok {
+				r.obvious = true
+				note += fmt.Sprintf(", %s was safe", mj.FormatTile(safe))
+			}
+		}
+		r.notes = append(r.notes, note)
+	}
+	return r, nil
+}
+
+// waiting reports whether the seat was clearly waiting to win: it had declared riichi, or
+// it had called every set but one.
+func waiting(d decision, s int) bool {
+	return s < len(d.riichi) && d.riichi[s] || len(d.melds[s]) >= d.rules.Sets()-1
+}
+
+func waitingReason(d decision, s int) string {
+	if s < len(d.riichi) && d.riichi[s] {
+		return "in riichi"
+	}
+	return fmt.Sprintf("with %d melds", len(d.melds[s]))
+}
+
+// safeTile returns a tile in the hand, other than the discard, that cannot deal into the
+// seat.
+func safeTile(d decision, s int, visible mj.Counter) (mj.Tile, bool) {
+	m := danger.Model{Rules: d.rules}
+	o := danger.Opponent{Discards: d.discards[s], Melds: d.melds[s]}
+	ds := m.Discards(d.hand, visible, []danger.Opponent{o})
+	for _, c := range ds {
+		if c.Danger == 0 && c.Tile != d.discard {
+			return c.Tile, true
+		}
+	}
+	return mj.Tile{}, false
+}
+
+// seen returns the tiles that the player could see outside the hand: every discard, and
+// the tiles of every meld that did not come from a discard. Which tile of a chi was
+// claimed is not recorded, so the first of its tiles that is among the discards is taken
+// to be the claimed one.
+func seen(d decision) mj.Counter {
+	v := mj.Visible{Rules: d.rules}
+	left := make(map[mj.Tile]int)
+	for _, ds := range d.discards {
+		for _, t := range ds {
+			v.Discard(t)
+			left[t]++
+		}
+	}
+	for _, ms := range d.melds {
+		for _, m := range ms {
+			claimed := mj.Tile{}
+			if !m.Concealed {
+				for _, t := range m.Tiles() {
+					if left[t] > 0 {
+						left[t]--
+						claimed = t
+						break
+					}
+				}
+			}
+			v.Meld(m, claimed)
+		}
+	}
+	return v.Public()
+}
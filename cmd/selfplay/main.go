@@ -0,0 +1,154 @@
+// Command selfplay plays many seeded games between computer players and reports how each
+// player did. The players are rotated through the seats, so that every player spends the
+// same number of games as the dealer. Each game is written to the output as a line of
+// JSON, and a summary with Elo ratings is printed to stderr.
+//
+// Usage:
+//   selfplay -games 1000 -players greedy,efficiency,random,greedy -rules "Hong Kong"
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/agent"
+	"github.com/nik0sc/mj/game"
+)
+
+// gameLine is one line of the output.
+type gameLine struct {
+	Game int   `json:"game"`
+	Seed int64 `json:"seed"`
+	// Seats holds the index into the player list of the player in each seat.
+	Seats  []int  `json:"seats"`
+	Winner int    `json:"winner"`
+	From   int    `json:"from"`
+	Tile   string `json:"tile,omitempty"`
+	Points int    `json:"points"`
+	Turns  int    `json:"turns"`
+	Error  string `json:"error,omitempty"`
+}
+
+var makers = map[string]func(seed int64) agent.Agent{
+	"random":     func(seed int64) agent.Agent { return &agent.Random{Seed: seed} },
+	"greedy":     func(seed int64) agent.Agent { return &agent.Greedy{Seed: seed} },
+	"efficiency": func(seed int64) agent.Agent { return &agent.Efficiency{Seed: seed} },
+}
+
+func main() {
+	games := flag.Int("games", 1000, "number of games to play")
+	seed := flag.Int64("seed", 1, "seed of the first game")
+	rulesName := flag.String("rules", mj.HongKong.Name, "name of the preset ruleset")
+	players := flag.String("players", "greedy,efficiency,random,greedy", "comma-separated agents, one per seat")
+	workers := flag.Int("workers", runtime.GOMAXPROCS(0), "number of games to play at once")
+	out := flag.String("out", "", "file to write the games to, instead of stdout")
+	k := flag.Float64("k", 16, "Elo K-factor")
+	flag.Parse()
+
+	rs, ok := preset(*rulesName)
+	if !ok {
+		fatalf("unknown ruleset %q", *rulesName)
+	}
+	names := strings.Split(*players, ",")
+	if len(names) != rs.Players() {
+		fatalf("%d players for %s, which needs %d", len(names), rs.Name, rs.Players())
+	}
+	for _, name := range names {
+		if makers[name] == nil {
+			fatalf("unknown agent %q", name)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+
+	lines := play(rs, names, *games, *seed, *workers)
+	enc := json.NewEncoder(bw)
+	st := newStats(names, *k)
+	for _, l := range lines {
+		if err := enc.Encode(l); err != nil {
+			fatalf("%v", err)
+		}
+		st.add(l)
+	}
+	st.write(os.Stderr)
+}
+
+// play plays the games on several goroutines, and returns them in order.
+func play(rs mj.Ruleset, names []string, games int, seed int64, workers int) []gameLine {
+	n := len(names)
+	lines := make([]gameLine, games)
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for g := range next {
+				l := gameLine{Game: g, Seed: seed + int64(g), Seats: make([]int, n)}
+				agents := make([]agent.Agent, n)
+				for s := range agents {
+					p := (s + g) % n
+					l.Seats[s] = p
+					agents[s] = makers[names[p]](l.Seed*int64(n) + int64(p))
+				}
+
+				r, err := game.Game{Rules: rs, Seed: l.Seed, Agents: agents}.Play()
+				if err != nil {
+					l.Error = err.Error()
+					r = game.Result{Winner: -1, From: -1}
+				}
+				l.Winner, l.From = seatToPlayer(l.Seats, r.Winner), seatToPlayer(l.Seats, r.From)
+				if r.Winner >= 0 {
+					l.Tile = mj.FormatTile(r.Tile)
+				}
+				l.Points, l.Turns = r.Points, r.Turns
+				lines[g] = l
+			}
+		}()
+	}
+	for g := 0; g < games; g++ {
+		next <- g
+	}
+	close(next)
+	wg.Wait()
+	return lines
+}
+
+func seatToPlayer(seats []int, seat int) int {
+	if seat < 0 {
+		return -1
+	}
+	return seats[seat]
+}
+
+func preset(name string) (mj.Ruleset, bool) {
+	for _, rs := range mj.Presets() {
+		if strings.EqualFold(rs.Name, name) {
+			return rs, true
+		}
+	}
+	return mj.Ruleset{}, false
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "selfplay: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+)
+
+const initialElo = 1500
+
+// stats accumulates the results of each player over many games.
+type stats struct {
+	names  []string
+	k      float64
+	games  int
+	errors int
+
+	wins, dealIns []int
+	points        []int
+	elo           []float64
+}
+
+func newStats(names []string, k float64) *stats {
+	n := len(names)
+	s := &stats{
+		names:   names,
+		k:       k,
+		wins:    make([]int, n),
+		dealIns: make([]int, n),
+		points:  make([]int, n),
+		elo:     make([]float64, n),
+	}
+	for i := range s.elo {
+		s.elo[i] = initialElo
+	}
+	return s
+}
+
+// add records a game. The winner takes the points from the player who dealt in, or
+// from every other player if the winning tile was drawn.
+func (s *stats) add(l gameLine) {
+	s.games++
+	if l.Error != "" {
+		s.errors++
+		return
+	}
+
+	n := len(s.names)
+	if l.Winner >= 0 {
+		s.wins[l.Winner]++
+		if l.From == l.Winner {
+			for p := range s.points {
+				if p != l.Winner {
+					s.points[p] -= l.Points
+					s.points[l.Winner] += l.Points
+				}
+			}
+		} else {
+			s.dealIns[l.From]++
+			s.points[l.From] -= l.Points
+			s.points[l.Winner] += l.Points
+		}
+	}
+
+	// every pair of players is a match: the winner beats everyone else, and the rest
+	// draw with each other
+	delta := make([]float64, n)
+	for a := 0; a < n; a++ {
+		for b := a + 1; b < n; b++ {
+			score := 0.5
+			switch l.Winner {
+			case a:
+				score = 1
+			case b:
+				score = 0
+			}
+			expected := 1 / (1 + math.Pow(10, (s.elo[b]-s.elo[a])/400))
+			d := s.k / float64(n-1) * (score - expected)
+			delta[a] += d
+			delta[b] -= d
+		}
+	}
+	for p := range s.elo {
+		s.elo[p] += delta[p]
+	}
+}
+
+func (s *stats) write(w io.Writer) {
+	fmt.Fprintf(w, "%d games, %d errors\n", s.games, s.errors)
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "player\tagent\twin rate\tdeal-in rate\tavg points\telo")
+	for p, name := range s.names {
+		fmt.Fprintf(tw, "%d\t%s\t%.1f%%\t%.1f%%\t%.2f\t%.0f\n", p, name,
+			percent(s.wins[p], s.games), percent(s.dealIns[p], s.games),
+			float64(s.points[p])/math.Max(1, float64(s.games)), s.elo[p])
+	}
+	tw.Flush()
+}
+
+func percent(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(d)
+}
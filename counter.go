@@ -47,21 +47,11 @@ func NewCounter(m map[Tile]int) (Counter, error) {
 	return c, nil
 }
 
+// NewCounterAtStart creates a new Counter with every tile in the basic Hong Kong tileset:
+// four of each melding tile and one of each flower. Use TileSet.NewCounter for other
+// tilesets.
 func NewCounterAtStart() Counter {
-	c := Counter{m: make(map[Tile]int, NumUniqueMeldingTiles)}
-
-	// Wildly inefficient lol
-	for i := 0; i < 256; i++ {
-		t := UnmarshalTile(byte(i))
-		if t.CanMeld() {
-			c.m[t] = 4
-			c.n += 4
-		} else if t.Valid() {
-			c.m[t] = 1
-			c.n++
-		}
-	}
-	return c
+	return TileSet{}.NewCounter()
 }
 
 // Valid returns true if the Counter is valid and all the tiles in the Counter are valid.
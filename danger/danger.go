@@ -0,0 +1,199 @@
+// Package danger estimates the chance that discarding a tile deals into an opponent's
+// winning hand, from what can be seen of each opponent.
+package danger
+
+import (
+	"math"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/ukeire"
+)
+
+// expectedWaits is the typical number of distinct tiles that a waiting hand waits on.
+const expectedWaits = 2
+
+// Opponent is what can be seen of another player.
+type Opponent struct {
+	// Discards holds the opponent's discards in order, including discards that were
+	// claimed by other players.
+	Discards mj.Hand
+	// Melds holds the opponent's declared melds.
+	Melds []mj.Meld
+	// CallTurns holds the number of discards the opponent had made when each meld was
+	// declared. It may be left empty, in which case every meld is taken to be declared
+	// on the opponent's latest turn.
+	CallTurns []int
+}
+
+// Model estimates the danger of discards. It is a heuristic: each opponent is given a
+// chance of waiting from how far the game has gone, and each tile a chance of being
+// waited on from the shapes that could wait on it, given the unseen tiles.
+//
+// The following are taken into account:
+//  - A tile in the opponent's own discards is safe.
+//  - A two-sided wait is ruled out if the opponent discarded its other tile, so 4 is
+//    safer against an opponent who discarded 1 or 7 (suji).
+//  - A shape that needs tiles that have all been seen is ruled out, so an honour with
+//    three copies visible can only be a single wait.
+//  - An opponent whose melds are all in one suit, perhaps with honours, is likely going
+//    for a flush, which makes that suit and honours more dangerous and the other suits
+//    much safer.
+//  - Melds, especially late ones, bring an opponent closer to waiting.
+//
+// The zero value is ready to use, and uses the basic Hong Kong ruleset.
+type Model struct {
+	Rules mj.Ruleset
+}
+
+// Tenpai estimates the chance that the opponent is waiting to win.
+func (m Model) Tenpai(o Opponent) float64 {
+	progress := float64(len(o.Discards))
+	for i := range o.Melds {
+		turn := len(o.Discards)
+		if i < len(o.CallTurns) {
+			turn = o.CallTurns[i]
+		}
+		progress += 2 + math.Min(float64(turn), 12)/4
+	}
+	if len(o.Melds) >= m.Rules.Sets() {
+		return 1
+	}
+	return 1 / (1 + math.Exp(-(progress-12)/3))
+}
+
+// Tile estimates the chance that discarding the tile deals into the opponent. The unseen
+// Counter holds the tiles that the player has not seen, which are the tiles that may be
+// in the opponent's hand.
+func (m Model) Tile(t mj.Tile, o Opponent, unseen mj.Counter) float64 {
+	if !t.CanMeld() || contains(o.Discards, t) {
+		return 0
+	}
+	total := 0.0
+	for _, u := range m.Rules.Tiles.Unique() {
+		if u.CanMeld() && !contains(o.Discards, u) {
+			total += m.weight(u, o, unseen)
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	p := expectedWaits * m.weight(t, o, unseen) / total
+	return m.Tenpai(o) * math.Min(1, p)
+}
+
+// Danger returns the chance that discarding each distinct tile in the hand deals into
+// any of the opponents.
+func (m Model) Danger(hand mj.Hand, opponents []Opponent, unseen mj.Counter) map[mj.Tile]float64 {
+	out := make(map[mj.Tile]float64)
+	for _, t := range hand {
+		if _, ok := out[t]; ok {
+			continue
+		}
+		safe := 1.0
+		for _, o := range opponents {
+			safe *= 1 - m.Tile(t, o, unseen)
+		}
+		out[t] = 1 - safe
+	}
+	return out
+}
+
+// Discard is a candidate discard with both its tile acceptance and its danger.
+type Discard struct {
+	ukeire.Discard
+	// Danger is the chance that the discard deals into any opponent.
+	Danger float64
+}
+
+// Discards returns the tile acceptance of each discard, in the order of ukeire.Discards,
+// with the danger of each discard next to it. The visible Counter holds the tiles seen
+// outside the hand, as for ukeire.Discards.
+func (m Model) Discards(hand mj.Hand, visible mj.Counter, opponents []Opponent) []Discard {
+	unseen := make(map[mj.Tile]int)
+	held := hand.ToCount()
+	for _, t := range m.Rules.Tiles.Unique() {
+		if n := m.Rules.Tiles.Count(t) - held.Get(t) - visible.Get(t); n > 0 {
+			unseen[t] = n
+		}
+	}
+	uc, err := mj.NewCounter(unseen)
+	if err != nil {
+		panic(err)
+	}
+
+	danger := m.Danger(hand, opponents, uc)
+	ds := ukeire.Discards(hand, visible, m.Rules)
+	out := make([]Discard, len(ds))
+	for i, d := range ds {
+		out[i] = Discard{Discard: d, Danger: danger[d.Tile]}
+	}
+	return out
+}
+
+// weight counts the ways that the opponent's hand could be waiting on the tile. Each
+// partial set that waits on the tile is counted by the number of ways to hold it with
+// the unseen tiles, and scaled by how likely the opponent is to be collecting its suit.
+func (m Model) weight(t mj.Tile, o Opponent, unseen mj.Counter) float64 {
+	u := func(v int) float64 {
+		if v < 1 || v > 9 {
+			return 0
+		}
+		return float64(unseen.Get(mj.Tile{Suit: t.Suit, Value: mj.Value(v)}))
+	}
+	discarded := func(v int) bool {
+		return v >= 1 && v <= 9 && contains(o.Discards, mj.Tile{Suit: t.Suit, Value: mj.Value(v)})
+	}
+
+	n := float64(unseen.Get(t))
+	// single wait, and a pair waiting to become a peng
+	w := n + n*(n-1)/2
+
+	if t.IsBasic() && !m.Rules.NoChi {
+		v := int(t.Value)
+		// v-2 v-1 waiting on v, and v+1 v+2 waiting on v. Unless they are edge waits,
+		// these also wait on v-3 and v+3.
+		if v-2 == 1 || !discarded(v-3) {
+			w += u(v-2) * u(v-1)
+		}
+		if v+2 == 9 || !discarded(v+3) {
+			w += u(v+1) * u(v+2)
+		}
+		// closed wait
+		w += u(v-1) * u(v+1)
+	}
+	return w * suitFactor(t, o)
+}
+
+// suitFactor is how much more likely the opponent is to be waiting on the tile's suit,
+// given their melds.
+func suitFactor(t mj.Tile, o Opponent) float64 {
+	var flush mj.Suit
+	for _, m := range o.Melds {
+		s := m.Tile.Suit
+		switch {
+		case s == mj.Honour:
+		case flush == 0:
+			flush = s
+		case flush != s:
+			return 1
+		}
+	}
+	switch {
+	case flush == 0:
+		return 1
+	case t.Suit == flush:
+		return 2
+	case t.Suit == mj.Honour:
+		return 1.5
+	}
+	return 0.25
+}
+
+func contains(h mj.Hand, t mj.Tile) bool {
+	for _, t2 := range h {
+		if t2 == t {
+			return true
+		}
+	}
+	return false
+}
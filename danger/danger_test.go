@@ -0,0 +1,76 @@
+package danger
+
+import (
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func tile(s string) mj.Tile {
+	return mj.MustParseHand(s)[0]
+}
+
+func TestModel_Tenpai(t *testing.T) {
+	var m Model
+	early := Opponent{Discards: mj.MustParseHand("hn hw")}
+	late := Opponent{Discards: mj.MustParseHand("hn hw b1 b9 c1 c9 w1 w9 he hs hz hf hb b2")}
+	called := late
+	called.Melds = []mj.Meld{{Kind: mj.Peng, Tile: tile("hz")}}
+
+	pe, pl, pc := m.Tenpai(early), m.Tenpai(late), m.Tenpai(called)
+	if !(pe < pl && pl < pc) {
+		t.Errorf("tenpai should rise with discards and melds: %v %v %v", pe, pl, pc)
+	}
+	if pe > 0.1 {
+		t.Errorf("tenpai after 2 discards = %v, want small", pe)
+	}
+}
+
+func TestModel_Tile(t *testing.T) {
+	var m Model
+	unseen := mj.HongKong.NewCounterAtStart()
+	o := Opponent{Discards: mj.MustParseHand("b1 hn hw he hs c9 w9 c1 hf b9 hb b2 c3")}
+
+	if got := m.Tile(tile("b1"), o, unseen); got != 0 {
+		t.Errorf("danger of a discarded tile = %v, want 0", got)
+	}
+	suji, plain := m.Tile(tile("b4"), o, unseen), m.Tile(tile("w4"), o, unseen)
+	if suji >= plain {
+		t.Errorf("suji b4 %v should be safer than w4 %v", suji, plain)
+	}
+
+	seen := unseen.Map()
+	seen[tile("hz")] = 1
+	seenC, err := mj.NewCounter(seen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, b := m.Tile(tile("hz"), o, seenC), m.Tile(tile("hz"), o, unseen); a >= b {
+		t.Errorf("hz with 3 seen %v should be safer than with none seen %v", a, b)
+	}
+
+	flush := o
+	flush.Melds = []mj.Meld{{Kind: mj.Chi, Tile: tile("c4")}, {Kind: mj.Peng, Tile: tile("c7")}}
+	if a, b := m.Tile(tile("w5"), flush, unseen), m.Tile(tile("c5"), flush, unseen); a >= b {
+		t.Errorf("w5 %v should be safer than c5 %v against a coin flush", a, b)
+	}
+}
+
+func TestModel_Discards(t *testing.T) {
+	var m Model
+	hand := mj.MustParseHand("b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b4 b5 hb")
+	o := Opponent{Discards: mj.MustParseHand("hb b1 c1 w1 he hs hw hn c9 b9 w9 hf")}
+
+	ds := m.Discards(hand, o.Discards.ToCount(), []Opponent{o})
+	if len(ds) != 13 {
+		t.Fatalf("got %d discards, want 13", len(ds))
+	}
+	if ds[0].Tile != tile("hb") || ds[0].Danger != 0 {
+		t.Errorf("best discard = %+v, want a safe hb", ds[0])
+	}
+	for _, d := range ds {
+		if d.Danger < 0 || d.Danger > 1 {
+			t.Errorf("danger of %s = %v", d.Tile, d.Danger)
+		}
+	}
+}
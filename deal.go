@@ -0,0 +1,49 @@
+package mj
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Wall returns every tile in play in a random order.
+func (r Ruleset) Wall(rng *rand.Rand) Hand {
+	wall := r.Tiles.AllTiles()
+	rng.Shuffle(len(wall), wall.Swap)
+	return wall
+}
+
+// Deal shuffles a new wall and deals a starting hand to each player, beginning with the
+// dealer. Bonus tiles dealt to a player are set aside in bonus and replaced from the
+// end of the wall. The hands are returned in sorted order, and the rest of the wall is
+// returned with the next tile to draw first.
+//
+// Every hand, including the dealer's, has the Ruleset's Size in tiles. The dealer does
+// not get an extra tile: callers should have the dealer draw from the wall to start the
+// first turn, as game.Game does.
+func (r Ruleset) Deal(rng *rand.Rand) (hands []Hand, bonus []Hand, wall Hand) {
+	wall = r.Wall(rng)
+	n := r.Players()
+	hands = make([]Hand, n)
+	bonus = make([]Hand, n)
+
+	for p := 0; p < n; p++ {
+		hands[p] = make(Hand, 0, r.Size())
+		var t Tile
+		t, wall = wall[0], wall[1:]
+		for {
+			if !t.IsBonus() {
+				hands[p] = append(hands[p], t)
+				if len(hands[p]) == r.Size() {
+					break
+				}
+				t, wall = wall[0], wall[1:]
+			} else {
+				// replace from the back of the wall
+				bonus[p] = append(bonus[p], t)
+				t, wall = wall[len(wall)-1], wall[:len(wall)-1]
+			}
+		}
+		sort.Sort(hands[p])
+	}
+	return hands, bonus, wall
+}
@@ -0,0 +1,376 @@
+// Package game plays a hand of Mahjong between agents. It covers the basic flow of
+// drawing, discarding, claiming and winning, which is enough to pit computer players
+// against each other, but not the finer rules of any particular ruleset.
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/agent"
+	"github.com/nik0sc/mj/handcheck"
+	"github.com/nik0sc/mj/record"
+	"github.com/nik0sc/mj/score"
+)
+
+// ErrBadMove is returned when an agent discards a tile it does not have, or makes a
+// claim that it was not offered.
+var ErrBadMove = errors.New("bad move")
+
+// Game is one hand of Mahjong. Seat 0 is the dealer and sits East, and play goes up
+// through the seats. The prevailing wind is East.
+//
+// Agents may claim discards to form melds, and win on a discard or on their own draw.
+// The earliest seat after the discarder that wins takes priority, then a peng or gang,
+// then a chi. Gangs may only be formed by claiming a discard, and the replacement tile
+// is drawn from the back of the wall.
+type Game struct {
+	// Rules gives the tiles, hand size and number of players. If the Ruleset names a
+	// scorer, a hand that the scorer rejects cannot win, and the winner's points are
+	// the scorer's total. Otherwise, every win is worth 1 point.
+	Rules mj.Ruleset
+	// Seed seeds the shuffle of the wall.
+	Seed int64
+	// Agents holds the player in each seat.
+	Agents []agent.Agent
+	// Log, if not nil, is written every draw, discard, claim, bonus tile and win, after
+	// a start entry with the seed, rules and dealt hands. See package record.
+	Log *record.Writer
+}
+
+// Result is the outcome of a Game.
+type Result struct {
+	// Winner is the seat that won, or -1 if the wall ran out.
+	Winner int
+	// From is the seat that discarded the winning tile, or Winner if it was drawn, or -1
+	// if nobody won.
+	From int
+	// Tile is the winning tile.
+	Tile mj.Tile
+	// Points is the score of the winning hand.
+	Points int
+	// Turns is the number of discards made.
+	Turns int
+}
+
+// SelfDrawn reports whether the winner drew the winning tile.
+func (r Result) SelfDrawn() bool {
+	return r.Winner >= 0 && r.From == r.Winner
+}
+
+type table struct {
+	g      Game
+	scorer score.Scorer
+	hands  []mj.Hand
+	melds  [][]mj.Meld
+	bonus  []mj.Hand
+	wall   mj.Hand
+	turns  int
+	logErr error
+}
+
+// Play plays the game to the end.
+func (g Game) Play() (Result, error) {
+	n := g.Rules.Players()
+	if len(g.Agents) != n {
+		return Result{}, fmt.Errorf("%d agents for %d players", len(g.Agents), n)
+	}
+
+	t := &table{g: g, melds: make([][]mj.Meld, n)}
+	if g.Rules.Scoring != "" {
+		s, err := score.For(g.Rules)
+		if err != nil {
+			return Result{}, err
+		}
+		t.scorer = s
+	}
+	t.hands, t.bonus, t.wall = g.Rules.Deal(rand.New(rand.NewSource(g.Seed)))
+	if g.Log != nil {
+		t.logErr = g.Log.Write(record.Start(g.Seed, g.Rules, t.hands, t.bonus))
+	}
+	r, err := t.play()
+	if err == nil && t.logErr != nil {
+		err = fmt.Errorf("writing log: %w", t.logErr)
+	}
+	return r, err
+}
+
+func (t *table) play() (Result, error) {
+	n := len(t.hands)
+	for seat, a := range t.g.Agents {
+		h := make(mj.Hand, len(t.hands[seat]))
+		copy(h, t.hands[seat])
+		a.Observe(agent.Event{Kind: agent.Start, Seat: seat, Hand: h})
+	}
+	for seat, b := range t.bonus {
+		for _, tile := range b {
+			t.send(agent.Event{Kind: agent.Bonus, Seat: seat, Tile: tile})
+		}
+	}
+
+	seat, draw, afterGang := 0, true, false
+	for {
+		if draw {
+			tile, ok := t.draw(seat, afterGang)
+			if !ok {
+				t.send(agent.Event{Kind: agent.Exhausted})
+				t.log(agent.Event{Kind: agent.Exhausted}, 0)
+				return Result{Winner: -1, From: -1, Turns: t.turns}, nil
+			}
+			if points, ok := t.canWin(seat, t.hands[seat], tile, true, afterGang); ok {
+				e := agent.Event{Kind: agent.Draw, Seat: seat, Tile: tile}
+				if t.g.Agents[seat].Claim(t.view(seat), e, []agent.Claim{{Win: true}}).Win {
+					return t.win(seat, seat, tile, points), nil
+				}
+			}
+		}
+
+		d := t.g.Agents[seat].Discard(t.view(seat))
+		if !t.take(seat, d) {
+			return Result{}, fmt.Errorf("%w: seat %d discarded %s, which it does not have", ErrBadMove, seat, d)
+		}
+		t.send(agent.Event{Kind: agent.Discard, Seat: seat, Tile: d})
+		t.log(agent.Event{Kind: agent.Discard, Seat: seat, Tile: d}, 0)
+		t.turns++
+
+		claimer, c, points, err := t.claims(seat, d)
+		if err != nil {
+			return Result{}, err
+		}
+		switch {
+		case claimer < 0:
+			seat, draw, afterGang = (seat+1)%n, true, false
+		case c.Win:
+			return t.win(claimer, seat, d, points), nil
+		default:
+			claimed := d
+			for _, mt := range c.Meld.Tiles() {
+				if mt == claimed {
+					claimed = mj.Tile{}
+				} else {
+					t.take(claimer, mt)
+				}
+			}
+			c.Meld.Concealed = false
+			t.melds[claimer] = append(t.melds[claimer], c.Meld)
+			e := agent.Event{Kind: agent.Meld, Seat: claimer, Tile: d, From: seat, Meld: c.Meld}
+			t.send(e)
+			t.log(e, 0)
+			seat = claimer
+			draw = c.Meld.Kind == mj.Gang
+			afterGang = draw
+		}
+	}
+}
+
+// draw draws a tile for the seat, from the back of the wall after a gang, and replaces
+// bonus tiles from the back of the wall. It returns false if the wall runs out.
+func (t *table) draw(seat int, fromBack bool) (mj.Tile, bool) {
+	for len(t.wall) > 0 {
+		var tile mj.Tile
+		if fromBack {
+			tile, t.wall = t.wall[len(t.wall)-1], t.wall[:len(t.wall)-1]
+		} else {
+			tile, t.wall = t.wall[0], t.wall[1:]
+		}
+		if tile.IsBonus() {
+			t.bonus[seat] = append(t.bonus[seat], tile)
+			t.send(agent.Event{Kind: agent.Bonus, Seat: seat, Tile: tile})
+			t.log(agent.Event{Kind: agent.Bonus, Seat: seat, Tile: tile}, 0)
+			fromBack = true
+			continue
+		}
+
+		t.hands[seat] = t.hands[seat].Append(tile)
+		sort.Sort(t.hands[seat])
+		t.log(agent.Event{Kind: agent.Draw, Seat: seat, Tile: tile}, 0)
+		for s, a := range t.g.Agents {
+			e := agent.Event{Kind: agent.Draw, Seat: seat}
+			if s == seat {
+				e.Tile = tile
+			}
+			a.Observe(e)
+		}
+		return tile, true
+	}
+	return mj.Tile{}, false
+}
+
+// claims offers the discard to the other seats and returns the claim that takes
+// priority, or -1 if every seat passes.
+func (t *table) claims(from int, d mj.Tile) (claimer int, c agent.Claim, points int, err error) {
+	n := len(t.hands)
+	claimer = -1
+	rank := 0
+	e := agent.Event{Kind: agent.Discard, Seat: from, Tile: d}
+	for i := 1; i < n; i++ {
+		seat := (from + i) % n
+		options, pts := t.options(seat, d, i == 1)
+		if len(options) == 0 {
+			continue
+		}
+		choice := t.g.Agents[seat].Claim(t.view(seat), e, options)
+		if choice.Pass() {
+			continue
+		}
+		if !offered(choice, options) {
+			return 0, agent.Claim{}, 0, fmt.Errorf("%w: seat %d made a claim it was not offered: %+v", ErrBadMove, seat, choice)
+		}
+
+		r := 1
+		switch {
+		case choice.Win:
+			r = 3
+		case choice.Meld.Kind != mj.Chi:
+			r = 2
+		}
+		if r > rank {
+			claimer, c, points, rank = seat, choice, pts, r
+		}
+	}
+	return claimer, c, points, nil
+}
+
+// options returns the claims that the seat can make on a discard, and the points for
+// winning on it. Only the next seat may chi.
+func (t *table) options(seat int, d mj.Tile, next bool) ([]agent.Claim, int) {
+	var out []agent.Claim
+	hand := t.hands[seat]
+	points, win := t.canWin(seat, hand.Append(d), d, false, false)
+	if win {
+		out = append(out, agent.Claim{Win: true})
+	}
+
+	cnt := hand.ToCount()
+	if cnt.Get(d) >= 2 {
+		out = append(out, agent.Claim{Meld: mj.Meld{Kind: mj.Peng, Tile: d}})
+	}
+	if cnt.Get(d) >= 3 && len(t.wall) > 0 {
+		out = append(out, agent.Claim{Meld: mj.Meld{Kind: mj.Gang, Tile: d}})
+	}
+	if next && !t.g.Rules.NoChi && d.IsBasic() {
+		for v := int(d.Value) - 2; v <= int(d.Value); v++ {
+			m := mj.Meld{Kind: mj.Chi, Tile: mj.Tile{Suit: d.Suit, Value: mj.Value(v)}}
+			if v < 1 || !m.Valid() {
+				continue
+			}
+			ok := true
+			for _, mt := range m.Tiles() {
+				if mt != d && cnt.Get(mt) == 0 {
+					ok = false
+				}
+			}
+			if ok {
+				out = append(out, agent.Claim{Meld: m})
+			}
+		}
+	}
+	return out, points
+}
+
+// canWin checks whether the concealed tiles, which include the winning tile, make a
+// winning hand with the seat's melds.
+func (t *table) canWin(seat int, concealed mj.Hand, tile mj.Tile, selfDrawn, afterGang bool) (int, bool) {
+	if handcheck.Shanten(concealed, t.g.Rules) != -1 {
+		return 0, false
+	}
+	if t.scorer == nil {
+		return 1, true
+	}
+
+	b, err := t.scorer.Score(score.Win{
+		Concealed:    concealed,
+		Melds:        t.melds[seat],
+		Bonus:        t.bonus[seat],
+		WinningTile:  tile,
+		SelfDrawn:    selfDrawn,
+		Seat:         mj.East + mj.Value(seat),
+		Round:        mj.East,
+		Rules:        t.g.Rules,
+		LastWallTile: len(t.wall) == 0,
+		AfterGang:    afterGang,
+	})
+	if err != nil {
+		return 0, false
+	}
+	return b.Total, true
+}
+
+func (t *table) win(seat, from int, tile mj.Tile, points int) Result {
+	e := agent.Event{Kind: agent.Win, Seat: seat, Tile: tile, From: from}
+	t.send(e)
+	t.log(e, points)
+	return Result{Winner: seat, From: from, Tile: tile, Points: points, Turns: t.turns}
+}
+
+// take removes a tile from the seat's hand, and returns false if it is not there.
+func (t *table) take(seat int, tile mj.Tile) bool {
+	for i, ht := range t.hands[seat] {
+		if ht == tile {
+			t.hands[seat] = t.hands[seat].Remove(i)
+			return true
+		}
+	}
+	return false
+}
+
+func (t *table) view(seat int) agent.View {
+	h := make(mj.Hand, len(t.hands[seat]))
+	copy(h, t.hands[seat])
+	ms := make([]mj.Meld, len(t.melds[seat]))
+	copy(ms, t.melds[seat])
+	return agent.View{Seat: seat, Rules: t.g.Rules, Hand: h, Melds: ms}
+}
+
+func (t *table) send(e agent.Event) {
+	for _, a := range t.g.Agents {
+		a.Observe(e)
+	}
+}
+
+// log writes the event to the game's log, if it has one. After the first error,
+// nothing more is written.
+func (t *table) log(e agent.Event, points int) {
+	if t.g.Log == nil || t.logErr != nil {
+		return
+	}
+	t.logErr = t.g.Log.Write(entry(e, points))
+}
+
+// entry returns the log entry for an event. Draw events must have their tile set. Start
+// events are not converted: use record.Start instead.
+func entry(ev agent.Event, points int) record.Entry {
+	e := record.Entry{Seat: ev.Seat}
+	switch ev.Kind {
+	case agent.Draw:
+		e.Type, e.Tile = record.TypeDraw, mj.FormatTile(ev.Tile)
+	case agent.Discard:
+		e.Type, e.Tile = record.TypeDiscard, mj.FormatTile(ev.Tile)
+	case agent.Bonus:
+		e.Type, e.Tile = record.TypeBonus, mj.FormatTile(ev.Tile)
+	case agent.Meld:
+		e.Type, e.From, e.Meld = record.TypeMeld, ev.From, record.FormatMeld(ev.Meld)
+		if ev.Tile.Valid() {
+			e.Tile = mj.FormatTile(ev.Tile)
+		}
+	case agent.Win:
+		e.Type, e.From, e.Tile, e.Points = record.TypeWin, ev.From, mj.FormatTile(ev.Tile), points
+	case agent.Exhausted:
+		e = record.Entry{Type: record.TypeExhausted}
+	default:
+		panic(fmt.Sprintf("entry: cannot convert %s event", ev.Kind))
+	}
+	return e
+}
+
+func offered(c agent.Claim, options []agent.Claim) bool {
+	for _, o := range options {
+		if o.Win == c.Win && o.Meld.Kind == c.Meld.Kind && o.Meld.Tile == c.Meld.Tile {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/agent"
+	"github.com/nik0sc/mj/record"
+)
+
+// counting wraps an agent and counts the events it sees.
+type counting struct {
+	agent.Agent
+	events map[agent.EventKind]int
+}
+
+func (c *counting) Observe(e agent.Event) {
+	if c.events == nil {
+		c.events = make(map[agent.EventKind]int)
+	}
+	c.events[e.Kind]++
+	c.Agent.Observe(e)
+}
+
+func agents(rs mj.Ruleset, seed int64) []agent.Agent {
+	out := make([]agent.Agent, rs.Players())
+	for i := range out {
+		out[i] = &counting{Agent: &agent.Greedy{Seed: seed + int64(i)}}
+	}
+	return out
+}
+
+func TestGame_Play(t *testing.T) {
+	for _, rs := range []mj.Ruleset{mj.HongKong, mj.Sanma, mj.Singapore} {
+		for seed := int64(0); seed < 5; seed++ {
+			as := agents(rs, seed)
+			r, err := Game{Rules: rs, Seed: seed, Agents: as}.Play()
+			if err != nil {
+				t.Fatalf("%s seed %d: %v", rs.Name, seed, err)
+			}
+			if r.Winner >= rs.Players() || r.Turns == 0 {
+				t.Errorf("%s seed %d: unexpected result %+v", rs.Name, seed, r)
+			}
+			if r.Winner >= 0 && (r.Points < 0 || !r.Tile.Valid()) {
+				t.Errorf("%s seed %d: win with bad points or tile: %+v", rs.Name, seed, r)
+			}
+
+			ev := as[0].(*counting).events
+			if ev[agent.Start] != 1 || ev[agent.Discard] != r.Turns {
+				t.Errorf("%s seed %d: events %v for %d turns", rs.Name, seed, ev, r.Turns)
+			}
+
+			again, err := Game{Rules: rs, Seed: seed, Agents: agents(rs, seed)}.Play()
+			if err != nil || again != r {
+				t.Errorf("%s seed %d: replay gave %+v, %v, want %+v", rs.Name, seed, again, err, r)
+			}
+		}
+	}
+}
+
+func TestGame_Log(t *testing.T) {
+	for _, rs := range []mj.Ruleset{mj.HongKong, mj.Sanma, mj.Singapore} {
+		for seed := int64(0); seed < 5; seed++ {
+			var buf bytes.Buffer
+			r, err := Game{Rules: rs, Seed: seed, Agents: agents(rs, seed), Log: record.NewWriter(&buf)}.Play()
+			if err != nil {
+				t.Fatalf("%s seed %d: %v", rs.Name, seed, err)
+			}
+			entries, err := record.Read(&buf)
+			if err != nil {
+				t.Fatalf("%s seed %d: %v", rs.Name, seed, err)
+			}
+			tb, err := record.Verify(entries)
+			if err != nil {
+				t.Fatalf("%s seed %d: %v", rs.Name, seed, err)
+			}
+
+			if tb.Wall == nil || tb.Winner != r.Winner || !tb.Over {
+				t.Errorf("%s seed %d: replayed %+v for %+v", rs.Name, seed, tb, r)
+			}
+			discards := 0
+			for _, d := range tb.Discards {
+				discards += len(d)
+			}
+			if discards != r.Turns {
+				t.Errorf("%s seed %d: %d discards replayed for %d turns", rs.Name, seed, discards, r.Turns)
+			}
+			if last := entries[len(entries)-1]; r.Winner >= 0 && last.Points != r.Points {
+				t.Errorf("%s seed %d: logged %d points, want %d", rs.Name, seed, last.Points, r.Points)
+			}
+		}
+	}
+}
+
+// cheat discards a tile it does not have.
+type cheat struct{ agent.Random }
+
+func (c *cheat) Discard(v agent.View) mj.Tile {
+	return mj.JokerTile
+}
+
+func TestGame_PlayErrors(t *testing.T) {
+	as := agents(mj.HongKong, 0)
+	if _, err := (Game{Agents: as[:3]}).Play(); err == nil {
+		t.Error("no error for too few agents")
+	}
+
+	as[0] = &cheat{}
+	if _, err := (Game{Agents: as}).Play(); !errors.Is(err, ErrBadMove) {
+		t.Errorf("err = %v, want ErrBadMove", err)
+	}
+}
+
+func Test_entry(t *testing.T) {
+	c1 := mj.Tile{Suit: mj.Coin, Value: 1}
+	for _, tt := range []struct {
+		ev     agent.Event
+		points int
+		want   record.Entry
+	}{
+		{agent.Event{Kind: agent.Draw, Seat: 1, Tile: c1}, 0, record.Entry{Type: record.TypeDraw, Seat: 1, Tile: "c1"}},
+		{agent.Event{Kind: agent.Meld, Seat: 0, From: 1, Tile: c1, Meld: mj.Meld{Kind: mj.Peng, Tile: c1}}, 0,
+			record.Entry{Type: record.TypeMeld, Seat: 0, From: 1, Tile: "c1", Meld: "peng c1"}},
+		{agent.Event{Kind: agent.Meld, Seat: 2, Meld: mj.Meld{Kind: mj.Gang, Tile: c1, Concealed: true}}, 0,
+			record.Entry{Type: record.TypeMeld, Seat: 2, Meld: "cgang c1"}},
+		{agent.Event{Kind: agent.Win, Seat: 0, From: 3, Tile: c1}, 8,
+			record.Entry{Type: record.TypeWin, Seat: 0, From: 3, Tile: "c1", Points: 8}},
+		{agent.Event{Kind: agent.Exhausted, Seat: 2}, 0, record.Entry{Type: record.TypeExhausted}},
+	} {
+		if got := entry(tt.ev, tt.points); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("entry(%+v) = %+v, want %+v", tt.ev, got, tt.want)
+		}
+	}
+}
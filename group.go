@@ -17,11 +17,19 @@ type Group struct {
 	Pairs Hand
 	// All the leftover tiles.
 	Free Hand
+	// Each tile is the tile that a joker stands for in one of the other fields.
+	// For example, a peng of b1 made from two b1s and a joker has b1 in both
+	// Pengs and Jokers. Jokers that are not used are in Free instead.
+	Jokers Hand
 }
 
 // ToHand expands the Pengs, Chis and Pairs into their full tile sequences and
-// recreates the original Hand.
+// recreates the original Hand. Tiles that jokers stand for are replaced with jokers.
 func (g Group) ToHand() Hand {
+	if len(g.Jokers) > 0 {
+		return g.ToCount().ToHand(true)
+	}
+
 	var h Hand
 
 	for _, t := range g.Pengs {
@@ -73,6 +81,11 @@ func (g Group) ToCount() Counter {
 		m[t]++
 	}
 
+	for _, t := range g.Jokers {
+		m[t]--
+		m[JokerTile]++
+	}
+
 	cnt, err := NewCounter(m)
 	if err != nil {
 		panic("cannot build counter from result: " + err.Error())
@@ -82,7 +95,7 @@ func (g Group) ToCount() Counter {
 }
 
 // String returns the human-readable representation of this Group, in the order
-// Pengs, Chis, Pairs and Free.
+// Pengs, Chis, Pairs and Free, followed by the tiles that jokers stand for.
 func (g Group) String() string {
 	var ss []string
 
@@ -103,6 +116,9 @@ func (g Group) String() string {
 	}
 
 	ss = append(ss, g.Free.String())
+	if len(g.Jokers) > 0 {
+		ss = append(ss, JokerTile.String()+"="+g.Jokers.String())
+	}
 	return strings.Join(ss, " ")
 }
 
@@ -130,6 +146,14 @@ func (g Group) Marshal() string {
 		b.WriteByte(t.Marshal())
 	}
 
+	// omitted without jokers, so that the encoding of other Groups is unchanged
+	if len(g.Jokers) > 0 {
+		b.WriteByte(',')
+		for _, t := range g.Jokers {
+			b.WriteByte(t.Marshal())
+		}
+	}
+
 	return b.String()
 }
 
@@ -157,6 +181,11 @@ func (g Group) Copy(sorted bool) Group {
 		copy(gNew.Free, g.Free)
 	}
 
+	if g.Jokers != nil {
+		gNew.Jokers = make(Hand, len(g.Jokers))
+		copy(gNew.Jokers, g.Jokers)
+	}
+
 	if sorted {
 		gNew.sort()
 	}
@@ -176,7 +205,8 @@ func (g Group) Score() int {
 	// a tile in a pair is worth 1,
 	// and a tile in a peng/chi is worth 1.333...
 
-	// A winning hand (including the 14th tile) when grouped has a score of 18.
+	// A winning 13-tile hand (including the 14th tile) when grouped has a score of 18.
+	// In general, a winning hand scores 4*Ruleset.Sets()+2.
 	// If waiting to complete a pair, the score is 4*4 = 16.
 	// If waiting to complete a peng, the score is 4*3 + 2*2 = also 16.
 	// If waiting to complete a chi, the score is 4*3 + 2*1 = 14.
@@ -195,6 +225,7 @@ func (g Group) sort() {
 	sort.Sort(g.Chis)
 	sort.Sort(g.Pairs)
 	sort.Sort(g.Free)
+	sort.Sort(g.Jokers)
 }
 
 // UnmarshalGroup is the inverse of Group.Marshal().
@@ -202,7 +233,7 @@ func UnmarshalGroup(repr string) Group {
 	var g Group
 
 	reprs := strings.Split(repr, ",")
-	if len(reprs) != 4 {
+	if len(reprs) != 4 && len(reprs) != 5 {
 		panic(fmt.Sprintf("wrong number of fields: %d", len(reprs)))
 	}
 
@@ -210,6 +241,9 @@ func UnmarshalGroup(repr string) Group {
 	g.Chis = UnmarshalHand(reprs[1])
 	g.Pairs = UnmarshalHand(reprs[2])
 	g.Free = UnmarshalHand(reprs[3])
+	if len(reprs) == 5 {
+		g.Jokers = UnmarshalHand(reprs[4])
+	}
 
 	return g
 }
@@ -0,0 +1,77 @@
+package handcheck
+
+import (
+	"github.com/nik0sc/mj"
+)
+
+// Complete finds every way to group a hand into 3-tile melds and exactly one pair.
+// Unlike the optimisers, which return one optimal grouping, this is useful for scoring,
+// where the grouping that scores the highest is not necessarily the one with the
+// most chis or pengs. Each returned Group has sorted fields and a nil Free field.
+// If the hand cannot be completely grouped, Complete returns nil.
+func Complete(hand mj.Hand) []mj.Group {
+	return CompleteWithRules(hand, mj.HongKong)
+}
+
+// CompleteWithRules is like Complete, but no chis are formed if the Ruleset forbids them.
+func CompleteWithRules(hand mj.Hand, rs mj.Ruleset) []mj.Group {
+	if len(hand)%3 != 2 {
+		return nil
+	}
+
+	hr, err := mj.NewHandRLE(hand.ToCount().Entries()...)
+	if err != nil {
+		panic("Counter and HandRLE don't agree on entries: " + err.Error())
+	}
+
+	var out []mj.Group
+	completeStep(hr, mj.Group{}, rs.NoChi, make(map[string]bool), &out)
+	return out
+}
+
+// completeStep always consumes the lowest free tile. The same grouping may still be
+// reached by taking the pair at different points, so seen tracks the groupings found.
+func completeStep(free mj.HandRLE, res mj.Group, noChi bool, seen map[string]bool, out *[]mj.Group) {
+	if free.Len() == 0 {
+		if len(res.Pairs) == 1 {
+			g := res.Copy(true)
+			repr := g.Marshal()
+			if !seen[repr] {
+				seen[repr] = true
+				*out = append(*out, g)
+			}
+		}
+		return
+	}
+
+	t := free.Entries()[0].Tile
+
+	if len(res.Pairs) == 0 {
+		if next, ok := free.TryPairAt(0); ok {
+			completeStep(next, mj.Group{
+				Pengs: res.Pengs,
+				Chis:  res.Chis,
+				Pairs: res.Pairs.Append(t),
+			}, noChi, seen, out)
+		}
+	}
+
+	if next, ok := free.TryPengAt(0); ok {
+		completeStep(next, mj.Group{
+			Pengs: res.Pengs.Append(t),
+			Chis:  res.Chis,
+			Pairs: res.Pairs,
+		}, noChi, seen, out)
+	}
+
+	if noChi {
+		return
+	}
+	if next, ok := free.TryChiAt(0); ok {
+		completeStep(next, mj.Group{
+			Pengs: res.Pengs,
+			Chis:  res.Chis.Append(t),
+			Pairs: res.Pairs,
+		}, noChi, seen, out)
+	}
+}
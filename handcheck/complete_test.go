@@ -0,0 +1,104 @@
+package handcheck
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func Test_Complete(t *testing.T) {
+	tests := []struct {
+		name string
+		hand string
+		want []mj.Group
+	}{
+		{
+			"all p real",
+			"b1 b1 b1 b2 b2 b2 b3 b3 b3 b4 b4 b4 b5 b5",
+			[]mj.Group{
+				{
+					Pengs: mj.MustParseHand("b1 b2 b3 b4"),
+					Pairs: mj.MustParseHand("b5"),
+				},
+				{
+					Pengs: mj.MustParseHand("b1"),
+					Chis:  mj.MustParseHand("b2 b2 b2"),
+					Pairs: mj.MustParseHand("b5"),
+				},
+				{
+					Chis:  mj.MustParseHand("b1 b1 b1"),
+					Pengs: mj.MustParseHand("b4"),
+					Pairs: mj.MustParseHand("b5"),
+				},
+				{
+					Pengs: mj.MustParseHand("b1"),
+					Chis:  mj.MustParseHand("b2 b3 b3"),
+					Pairs: mj.MustParseHand("b2"),
+				},
+			},
+		},
+		{
+			"mixed",
+			"c1 c2 c3 hz hz hz w5 w5 b7 b8 b9 b9 b9 b9",
+			[]mj.Group{
+				{
+					Pengs: mj.MustParseHand("b9 hz"),
+					Chis:  mj.MustParseHand("b7 c1"),
+					Pairs: mj.MustParseHand("w5"),
+				},
+			},
+		},
+		{
+			"not complete",
+			"w1 b7 w4 c5 b9 he w5 hf w5 c3 b8 hf hn hf",
+			nil,
+		},
+		{
+			"wrong length",
+			"b1 b1 b1",
+			nil,
+		},
+		{
+			"flower",
+			"b1 b1 b1 b2 b2 b2 b3 b3 b3 b4 b4 b4 b5 f1",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Complete(mj.MustParseHand(tt.hand))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d groups %v, want %d", len(got), got, len(tt.want))
+			}
+
+			found := make(map[string]bool)
+			for _, g := range got {
+				if g.Free != nil {
+					t.Errorf("free tiles in %v", g)
+				}
+				found[g.Marshal()] = true
+			}
+			for _, g := range tt.want {
+				if !found[g.Copy(true).Marshal()] {
+					t.Errorf("missing %v", g)
+				}
+			}
+			if len(got) > 0 && !reflect.DeepEqual(got[0].ToCount().Map(), mj.MustParseHand(tt.hand).ToCount().Map()) {
+				t.Errorf("tiles do not match: %v", got[0])
+			}
+		})
+	}
+}
+
+func Test_CompleteWithRules(t *testing.T) {
+	h := mj.MustParseHand("b1 b1 b1 b2 b2 b2 b3 b3 b3 b4 b4 b4 b5 b5")
+	if got := CompleteWithRules(h, mj.HongKong); len(got) != 4 {
+		t.Errorf("hong kong: got %d groups %v, want 4", len(got), got)
+	}
+	got := CompleteWithRules(h, mj.Sanma)
+	if len(got) != 1 || len(got[0].Chis) != 0 {
+		t.Errorf("sanma: got %v, want only pengs", got)
+	}
+}
@@ -5,4 +5,7 @@
 //
 // The optimisers will not detect special hands like thirteen orphans or all pairs.
 // They will also not group tiles into gangs/kongs. This is by design.
+//
+// Nothing here assumes a 13-tile hand, so the optimisers and Complete work for any
+// mj.Ruleset, including 16-tile Taiwanese hands.
 package handcheck
@@ -17,6 +17,8 @@ type GreedyChecker struct {
 	// is no possible winning interpretation of the hand
 	Split    bool
 	FailFast bool
+	// NoChi disables chis, for rulesets like mj.Sanma that forbid them.
+	NoChi bool
 }
 
 type gstate struct {
@@ -27,6 +29,7 @@ type gstate struct {
 }
 
 type gshared struct {
+	noChi     bool
 	stepCount int
 }
 
@@ -59,8 +62,7 @@ func (c GreedyChecker) Check(hand mj.Hand) mj.Group {
 }
 
 func (c GreedyChecker) start(h mj.Hand) mj.Group {
-	_ = c
-	s := gstate{h: h, shared: &gshared{}}
+	s := gstate{h: h, shared: &gshared{noChi: c.NoChi}}
 
 	r, ok := s.step()
 	if writeMetrics {
@@ -113,7 +115,7 @@ func (s gstate) step() (mj.Group, bool) {
 			if next.build.IsPeng() {
 				next.res.Pengs = next.res.Pengs.Append(next.build[0])
 				next.build = nil
-			} else if next.build.IsChi() {
+			} else if !s.shared.noChi && next.build.IsChi() {
 				next.res.Chis = next.res.Chis.Append(next.build[0])
 				next.build = nil
 			} else {
@@ -99,7 +99,7 @@ func Test_GreedyChecker_Check(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := GreedyChecker{tt.args.split, tt.args.failfast}
+			c := GreedyChecker{Split: tt.args.split, FailFast: tt.args.failfast}
 
 			h, err := mj.ParseHand(tt.hand)
 			if err != nil {
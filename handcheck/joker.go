@@ -0,0 +1,171 @@
+package handcheck
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/nik0sc/mj"
+)
+
+// JokerChecker implements an optimal hand checker for hands that may contain jokers.
+// Like the other optimal checkers, the result minimises the number of tiles not
+// participating in a meld, then maximises the number of 3-tile melds.
+// The zero value is safe to use immediately (without any optimisations).
+//
+// A joker may stand in for any tile in a peng, but not in a chi or a pair, and each
+// peng has at least one tile that is not a joker. This follows American rules, where
+// jokers may only be used in groups of three or more identical tiles. The tile that each joker stands for
+// is recorded in the Jokers field of the result, and jokers that could not be used are
+// left in Free.
+//
+// Under the hood, this uses mj.Counter to represent the free tiles at each subproblem,
+// like OptCountChecker.
+type JokerChecker struct {
+	// use memoisation to avoid O(2^n) running time
+	UseMemo bool
+	// NoChi disables chis, for rulesets like mj.Sanma that forbid them.
+	NoChi bool
+	// Metrics receives a summary of the search after each check, and a trace of each
+	// step if tracing is compiled in. If it is nil, nothing is written.
+	Metrics io.Writer
+}
+
+type ojstate struct {
+	// Like OptCountChecker, the result always has a nil Free field.
+	res    mj.Group
+	free   mj.Counter
+	jokers int
+	shared *shared
+	// out is where metrics and traces are written.
+	out io.Writer
+}
+
+// Check finds the optimal grouping for a hand.
+func (c JokerChecker) Check(hand mj.Hand) mj.Group {
+	h := make(mj.Hand, len(hand))
+	copy(h, hand)
+	sort.Sort(h)
+
+	shr := shared{noChi: c.NoChi}
+	if c.UseMemo {
+		shr.memo = make(map[string]string)
+	}
+	cnt := h.ToCount()
+	jokers := cnt.Get(mj.JokerTile)
+	m := cnt.Map()
+	delete(m, mj.JokerTile)
+	free, err := mj.NewCounter(m)
+	if err != nil {
+		panic(err)
+	}
+	out := c.Metrics
+	if out == nil {
+		out = ioutil.Discard
+	}
+	s := ojstate{mj.Group{}, free, jokers, &shr, out}
+
+	r := s.step()
+	shr.writeSummary(out)
+
+	// the tiles that jokers stand for were never in the hand
+	cmap := cnt.Map()
+	for _, t := range r.Jokers {
+		cmap[t]++
+		cmap[mj.JokerTile]--
+	}
+	sort.Sort(r.Jokers)
+	err = postprocessCountGroup(&r, cmap)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+func (s ojstate) step() mj.Group {
+	s.shared.enterStep(s.out, s.free)
+
+	if s.free.Len() == 0 {
+		return s.res
+	}
+
+	// the same free tiles may be reached with different numbers of jokers left
+	repr := s.free.Marshal() + string(rune('0'+s.jokers))
+	if r, ok := s.shared.getMemo(repr); ok {
+		return r
+	}
+
+	best := s.res
+	try := func(r mj.Group) {
+		if r.Score() > best.Score() {
+			best = r
+		}
+	}
+
+	s.free.ForEach(func(t mj.Tile, n int) bool {
+		if n == 0 {
+			// Counter.Remove leaves tiles with no copies behind
+			return true
+		}
+
+		if nextFree, ok := s.free.TryPeng(t); ok {
+			if traceSteps {
+				fmt.Fprintf(s.out, "peng: %s x%d\n", t, n)
+			}
+			try(s.next(nextFree, 0, func(g *mj.Group) { g.Pengs = g.Pengs.Append(t) }))
+		}
+
+		if nextFree, ok := s.free.TryPair(t); ok {
+			if traceSteps {
+				fmt.Fprintf(s.out, "pair: %s x%d\n", t, n)
+			}
+			try(s.next(nextFree, 0, func(g *mj.Group) { g.Pairs = g.Pairs.Append(t) }))
+
+			if s.jokers >= 1 {
+				if traceSteps {
+					fmt.Fprintf(s.out, "peng with joker: %s x%d\n", t, n)
+				}
+				try(s.next(nextFree, 1, func(g *mj.Group) { g.Pengs = g.Pengs.Append(t) }))
+			}
+		}
+
+		if s.jokers >= 2 && t.CanMeld() {
+			if traceSteps {
+				fmt.Fprintf(s.out, "peng with 2 jokers: %s x%d\n", t, n)
+			}
+			try(s.next(s.free.Remove(t), 2, func(g *mj.Group) { g.Pengs = g.Pengs.Append(t) }))
+		}
+
+		if s.shared.noChi {
+			return true
+		}
+		if nextFree, ok := s.free.TryChi(t); ok {
+			if traceSteps {
+				fmt.Fprintf(s.out, "chi: %s x%d\n", t, n)
+			}
+			try(s.next(nextFree, 0, func(g *mj.Group) { g.Chis = g.Chis.Append(t) }))
+		}
+		return true
+	})
+
+	s.shared.setMemo(repr, best)
+	return best
+}
+
+// next builds the state after a meld is formed using some jokers, and solves it.
+// The jokers stand for the last tile added to Pengs.
+func (s ojstate) next(free mj.Counter, jokers int, add func(g *mj.Group)) mj.Group {
+	g := mj.Group{
+		Pengs:  s.res.Pengs,
+		Chis:   s.res.Chis,
+		Pairs:  s.res.Pairs,
+		Jokers: s.res.Jokers,
+	}
+	add(&g)
+	for i := 0; i < jokers; i++ {
+		g.Jokers = g.Jokers.Append(g.Pengs[len(g.Pengs)-1])
+	}
+	return ojstate{g, free, s.jokers - jokers, s.shared, s.out}.step()
+}
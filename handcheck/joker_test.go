@@ -0,0 +1,84 @@
+package handcheck
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func Test_JokerChecker_Check(t *testing.T) {
+	tests := []struct {
+		name string
+		hand string
+		want mj.Group
+	}{
+		{
+			"no jokers",
+			"b1 b2 b3 b3 b4 b5 b5 b6 b7 b7 b8 b9 b9 b9",
+			mj.Group{
+				Chis:  mj.MustParseHand("b1 b3 b5 b7"),
+				Pairs: mj.MustParseHand("b9"),
+			},
+		},
+		{
+			"one joker",
+			"b1 b1 j1 c2 c3 c4 w5 w6 w7 c7 c8 c9 hf",
+			mj.Group{
+				Pengs:  mj.MustParseHand("b1"),
+				Chis:   mj.MustParseHand("c2 c7 w5"),
+				Free:   mj.MustParseHand("hf"),
+				Jokers: mj.MustParseHand("b1"),
+			},
+		},
+		{
+			"two jokers",
+			"b1 j1 j1 c2 c3 c4 w5 w6 w7 c7 c8 c9 hf hf",
+			mj.Group{
+				Pengs:  mj.MustParseHand("b1"),
+				Chis:   mj.MustParseHand("c2 c7 w5"),
+				Pairs:  mj.MustParseHand("hf"),
+				Jokers: mj.MustParseHand("b1 b1"),
+			},
+		},
+		{
+			"joker not in chi or pair",
+			"b1 b2 j1 hf",
+			mj.Group{
+				Free: mj.MustParseHand("b1 b2 hf j1"),
+			},
+		},
+		{
+			"unused joker",
+			"b1 b2 b3 c4 c4 j1",
+			mj.Group{
+				Pengs:  mj.MustParseHand("c4"),
+				Chis:   mj.MustParseHand("b1"),
+				Jokers: mj.MustParseHand("c4"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := mj.MustParseHand(tt.hand)
+			for _, c := range []JokerChecker{{}, {UseMemo: true}} {
+				got := c.Check(h)
+				if tt.want.Copy(true).Marshal() != got.Copy(true).Marshal() {
+					t.Errorf("memo=%t: want %v, got %v", c.UseMemo, tt.want, got)
+				}
+				if got.ToCount().Marshal() != h.ToCount().Marshal() {
+					t.Errorf("memo=%t: tiles do not match: %v", c.UseMemo, got)
+				}
+			}
+		})
+	}
+}
+
+func Test_JokerChecker_Metrics(t *testing.T) {
+	var buf bytes.Buffer
+	JokerChecker{UseMemo: true, Metrics: &buf}.Check(mj.MustParseHand("b1 b1 j1 c2 c3 c4"))
+	if !strings.HasPrefix(buf.String(), "shared: ") {
+		t.Errorf("metrics = %q", buf.String())
+	}
+}
@@ -33,6 +33,8 @@ type OptChecker struct {
 	UseMemo bool
 	// UseUnsafeMemo...
 	UseUnsafeMemo bool
+	// NoChi disables chis, for rulesets like mj.Sanma that forbid them.
+	NoChi bool
 }
 
 type ostate struct {
@@ -81,7 +83,7 @@ func (c OptChecker) Check(hand mj.Hand) mj.Group {
 }
 
 func (c OptChecker) start(h mj.Hand) mj.Group {
-	shr := shared{}
+	shr := shared{noChi: c.NoChi}
 	if c.UseMemo {
 		shr.memo = make(map[string]string)
 	}
@@ -145,6 +147,9 @@ func (s ostate) step() mj.Group {
 			}
 		}
 
+		if s.shared.noChi {
+			continue
+		}
 		if nextFree, ok := s.res.Free.TryChiAt(i); ok {
 			r := ostate{mj.Group{
 				Pengs: s.res.Pengs,
@@ -28,6 +28,8 @@ type OptCountChecker struct {
 	Split bool
 	// use memoisation to avoid O(2^n) running time
 	UseMemo bool
+	// NoChi disables chis, for rulesets like mj.Sanma that forbid them.
+	NoChi bool
 }
 
 type ocstate struct {
@@ -44,7 +46,7 @@ func (c OptCountChecker) Check(hand mj.Hand) mj.Group {
 	copy(h, hand)
 	sort.Sort(h)
 
-	shr := shared{}
+	shr := shared{noChi: c.NoChi}
 	if c.UseMemo {
 		shr.memo = make(map[string]string)
 	}
@@ -112,6 +114,9 @@ func (s ocstate) step() mj.Group {
 			}
 		}
 
+		if s.shared.noChi {
+			return true
+		}
 		if nextFree, ok := s.free.TryChi(t); ok {
 			if traceSteps {
 				fmt.Printf("chi: %s x%d\n", t, n)
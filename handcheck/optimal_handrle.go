@@ -26,6 +26,8 @@ type OptHandRLEChecker struct {
 	Split bool
 	// use memoisation to avoid O(2^n) running time
 	UseMemo bool
+	// NoChi disables chis, for rulesets like mj.Sanma that forbid them.
+	NoChi bool
 }
 
 type ohrstate struct {
@@ -40,7 +42,7 @@ func (c OptHandRLEChecker) Check(hand mj.Hand) mj.Group {
 	copy(h, hand)
 	sort.Sort(h)
 
-	shr := shared{}
+	shr := shared{noChi: c.NoChi}
 	if c.UseMemo {
 		shr.memo = make(map[string]string)
 	}
@@ -113,6 +115,9 @@ func (s ohrstate) step() mj.Group {
 			}
 		}
 
+		if s.shared.noChi {
+			return true
+		}
 		if nextFree, ok := s.free.TryChiAt(i); ok {
 			if traceSteps {
 				fmt.Printf("chi: %s x%d\n", e.Tile, e.Count)
@@ -152,6 +152,21 @@ func Test_OptHandRLEChecker_Check(t *testing.T) {
 	}
 }
 
+func Test_OptHandRLEChecker_NoChi(t *testing.T) {
+	h := mj.MustParseHand("b1 b2 b3 b3 b4 b5 c7 c7 c7 hz hz")
+	want := mj.Group{
+		Pengs: mj.MustParseHand("c7"),
+		Pairs: mj.MustParseHand("b3 hz"),
+		Free:  mj.MustParseHand("b1 b2 b4 b5"),
+	}
+
+	got := OptHandRLEChecker{UseMemo: true, NoChi: true}.Check(h)
+	sort.Sort(got.Free)
+	if want.Marshal() != got.Marshal() {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
 func Benchmark_OptHandRLEChecker_AllP(b *testing.B) {
 	hand, _ := mj.ParseHand("b1 b1 b1 b1 b1 b1 b1 b1 b1 b1 b1 b1 b1 b1")
 	benchmark_OptHandRLEChecker(b, hand)
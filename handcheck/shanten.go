@@ -0,0 +1,185 @@
+package handcheck
+
+import (
+	"fmt"
+
+	"github.com/nik0sc/mj"
+)
+
+// Shanten returns the number of tiles that a hand needs to exchange before it is waiting
+// to win. A waiting hand has a shanten of 0, and a complete hand has a shanten of -1.
+// The hand holds the concealed tiles only, and must have 3n+1 or 3n+2 tiles, where n is
+// the number of sets still to be formed in the concealed part of the hand. Shanten panics
+// for other hand sizes.
+//
+// Thirteen orphans and seven pairs are considered if the Ruleset allows them and the hand
+// has no declared melds, but the 16-tile form of seven pairs is not.
+//
+// Shanten counts sets and partial sets directly instead of building on the Checkers. A
+// Checker keeps the grouping with the most complete sets, which is not always the one
+// closest to winning when partial sets are taken into account, and it cannot say how far
+// an incomplete hand is from waiting. The wait package, which finds waits from a
+// Checker's grouping, imports this package, so the two are cross-checked in the ukeire
+// tests.
+func Shanten(hand mj.Hand, rs mj.Ruleset) int {
+	if len(hand)%3 == 0 {
+		panic(fmt.Sprintf("shanten: hand of %d tiles", len(hand)))
+	}
+
+	var counts shantenCounts
+	for _, t := range hand {
+		counts[shantenIndex(t)]++
+	}
+
+	best := counts.standard(len(hand)/3, rs.NoChi)
+	full := len(hand)/3 == rs.Sets()
+	if full && rs.Size() == 13 && rs.Allows(mj.SevenPairsHand) {
+		if s := counts.sevenPairs(rs.SevenPairsRepeat); s < best {
+			best = s
+		}
+	}
+	if full && rs.Allows(mj.ThirteenOrphansHand) {
+		if s := counts.thirteenOrphans(); s < best {
+			best = s
+		}
+	}
+	return best
+}
+
+// shantenCounts holds the number of each melding tile, indexed by shantenIndex. The last
+// entry collects tiles that cannot meld.
+type shantenCounts [35]int
+
+const shantenOther = 34
+
+// shantenIndex maps the basic suits to 0-26 and the honours to 27-33.
+func shantenIndex(t mj.Tile) int {
+	switch {
+	case t.IsBasic():
+		return int(t.Suit-mj.Bamboo)*9 + int(t.Value) - 1
+	case t.Suit == mj.Honour:
+		return 27 + int(t.Value-mj.East)
+	}
+	return shantenOther
+}
+
+// standard returns the shanten for a hand of sets and a pair. It tries every tile as
+// the pair, then counts the most complete sets and partial sets.
+func (c *shantenCounts) standard(sets int, noChi bool) int {
+	best := 2*sets - c.partial(sets, noChi)
+	for i := 0; i < shantenOther; i++ {
+		if c[i] < 2 {
+			continue
+		}
+		c[i] -= 2
+		if s := 2*sets - 1 - c.partial(sets, noChi); s < best {
+			best = s
+		}
+		c[i] += 2
+	}
+	return best
+}
+
+// partial returns the highest value of 2 for each set plus 1 for each partial set,
+// where no more than sets sets and partial sets are counted.
+func (c *shantenCounts) partial(sets int, noChi bool) int {
+	best := 0
+	var step func(i, melds, partials int)
+	step = func(i, melds, partials int) {
+		for i < shantenOther && c[i] == 0 {
+			i++
+		}
+		if i == shantenOther {
+			if melds+partials > sets {
+				partials = sets - melds
+			}
+			if v := 2*melds + partials; v > best {
+				best = v
+			}
+			return
+		}
+
+		suited := i < 27
+		pos := i % 9
+		if c[i] >= 3 {
+			c[i] -= 3
+			step(i, melds+1, partials)
+			c[i] += 3
+		}
+		if suited && !noChi && pos <= 6 && c[i+1] > 0 && c[i+2] > 0 {
+			c[i]--
+			c[i+1]--
+			c[i+2]--
+			step(i, melds+1, partials)
+			c[i]++
+			c[i+1]++
+			c[i+2]++
+		}
+		if c[i] >= 2 {
+			c[i] -= 2
+			step(i, melds, partials+1)
+			c[i] += 2
+		}
+		if suited && !noChi && pos <= 7 && c[i+1] > 0 {
+			c[i]--
+			c[i+1]--
+			step(i, melds, partials+1)
+			c[i]++
+			c[i+1]++
+		}
+		if suited && !noChi && pos <= 6 && c[i+2] > 0 {
+			c[i]--
+			c[i+2]--
+			step(i, melds, partials+1)
+			c[i]++
+			c[i+2]++
+		}
+
+		// leave the rest of this tile unused
+		n := c[i]
+		c[i] = 0
+		step(i+1, melds, partials)
+		c[i] = n
+	}
+	step(0, 0, 0)
+	return best
+}
+
+func (c *shantenCounts) sevenPairs(allowRepeat bool) int {
+	pairs, kinds := 0, 0
+	for i := 0; i < shantenOther; i++ {
+		if c[i] == 0 {
+			continue
+		}
+		kinds++
+		if allowRepeat {
+			pairs += c[i] / 2
+		} else if c[i] >= 2 {
+			pairs++
+		}
+	}
+	if pairs > 7 {
+		pairs = 7
+	}
+	s := 6 - pairs
+	if !allowRepeat && kinds < 7 {
+		s += 7 - kinds
+	}
+	return s
+}
+
+func (c *shantenCounts) thirteenOrphans() int {
+	kinds, pair := 0, 0
+	for i := 0; i < shantenOther; i++ {
+		if i < 27 && i%9 != 0 && i%9 != 8 {
+			continue
+		}
+		if c[i] > 0 {
+			kinds++
+		}
+		if c[i] >= 2 {
+			pair = 1
+		}
+	}
+	return 13 - kinds - pair
+}
@@ -0,0 +1,37 @@
+package handcheck
+
+import (
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestShanten(t *testing.T) {
+	tests := []struct {
+		name string
+		hand string
+		rs   mj.Ruleset
+		want int
+	}{
+		{"complete", "b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz hz hb hb", mj.HongKong, -1},
+		{"waiting", "b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz hz hb", mj.HongKong, 0},
+		{"waiting two-sided", "b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b4 b5", mj.HongKong, 0},
+		{"one away", "b1 b2 b3 c4 c5 c6 w7 w8 hz hz hb b4 b5", mj.HongKong, 1},
+		{"declared melds", "b1 b2 b3 hz", mj.HongKong, 0},
+		{"scattered", "b1 b4 b7 c2 c5 c8 w3 w6 w9 he hs hw hn", mj.HongKong, 6},
+		{"thirteen orphans", "b1 b9 c1 c9 w1 w9 he hs hw hn hz hf b5", mj.HongKong, 1},
+		{"thirteen orphans off", "b1 b9 c1 c9 w1 w9 he hs hw hn hz hf b5", mj.Ruleset{Specials: mj.NoSpecialHands}, 8},
+		{"seven pairs", "b1 b1 b4 b4 b7 b7 c2 c2 c5 c5 c8 w3 w6", mj.HongKong, 1},
+		{"seven pairs repeat", "b1 b1 b1 b1 b7 b7 c2 c2 c5 c5 c8 c8 w6", mj.HongKong, 2},
+		{"seven pairs allowed repeat", "b1 b1 b1 b1 b7 b7 c2 c2 c5 c5 c8 c8 w6", mj.MCR, 0},
+		{"no chi", "b1 b2 b3 c4 c5 c6 w1 w1 w1 hz hz hz hb", mj.Sanma, 4},
+		{"taiwanese", "b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz hz b5 b5 b6 b7", mj.Taiwanese, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Shanten(mj.MustParseHand(tt.hand), tt.rs); got != tt.want {
+				t.Errorf("Shanten() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
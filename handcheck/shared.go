@@ -17,6 +17,8 @@ const (
 )
 
 type shared struct {
+	// noChi is copied from the checker's options.
+	noChi     bool
 	memo      map[string]string
 	stepCount int
 	memoHits  int
@@ -0,0 +1,168 @@
+package handcheck
+
+import (
+	"sort"
+
+	"github.com/nik0sc/mj"
+)
+
+// Solver finds an optimal grouping like the other optimal checkers, but for a hand that
+// changes one tile at a time, as in a bot that draws and discards every turn. Melds
+// never span suits, so the Solver keeps the optimal grouping of each suit and only
+// solves the suit of a tile that is added or removed again. Solutions are memoised for
+// the lifetime of the Solver and shared between suits, turns and candidate discards.
+// The zero value is ready to use, and holds an empty hand.
+type Solver struct {
+	// NoChi disables chis, for rulesets like mj.Sanma that forbid them.
+	NoChi bool
+
+	suits   map[mj.Suit]mj.Hand
+	results map[mj.Suit]mj.Group
+	// memo maps a sorted hand to its optimal grouping, including Free.
+	memo map[solverKey]mj.Group
+}
+
+// solverKey is the Marshal of a sorted hand and the NoChi setting it was solved with,
+// which may change between calls.
+type solverKey struct {
+	hand  string
+	noChi bool
+}
+
+// SolverDiscard is the optimal grouping of the hand that is left after a discard.
+type SolverDiscard struct {
+	Tile  mj.Tile
+	Group mj.Group
+}
+
+// Set replaces the hand and solves every suit.
+func (s *Solver) Set(hand mj.Hand) {
+	s.suits = hand.Split(true)
+	s.results = make(map[mj.Suit]mj.Group, len(s.suits))
+	for suit, h := range s.suits {
+		s.results[suit] = s.solve(h)
+	}
+}
+
+// Add adds a tile to the hand, such as a drawn tile, and solves its suit.
+func (s *Solver) Add(t mj.Tile) {
+	if s.suits == nil {
+		s.Set(nil)
+	}
+	h := s.suits[t.Suit].Append(t)
+	sort.Sort(h)
+	s.suits[t.Suit] = h
+	s.results[t.Suit] = s.solve(h)
+}
+
+// Remove removes one copy of a tile from the hand, such as a discard, and solves its
+// suit. It returns false if the tile is not in the hand.
+func (s *Solver) Remove(t mj.Tile) bool {
+	h := s.suits[t.Suit]
+	for i, t2 := range h {
+		if t2 == t {
+			s.suits[t.Suit] = h.Remove(i)
+			s.results[t.Suit] = s.solve(s.suits[t.Suit])
+			return true
+		}
+	}
+	return false
+}
+
+// Hand returns the tiles in the hand, in sorted order.
+func (s *Solver) Hand() mj.Hand {
+	var h mj.Hand
+	for _, sh := range s.suits {
+		h = append(h, sh...)
+	}
+	sort.Sort(h)
+	return h
+}
+
+// Group returns the optimal grouping of the hand. The fields of the result are sorted.
+func (s *Solver) Group() mj.Group {
+	return s.combine(mj.Suit(0), mj.Group{})
+}
+
+// Discards returns the optimal grouping after each distinct discard from the hand, in
+// tile order. Only the suit of each discard is solved again, and the hand is not changed.
+func (s *Solver) Discards() []SolverDiscard {
+	var out []SolverDiscard
+	for _, t := range s.Hand() {
+		if len(out) > 0 && out[len(out)-1].Tile == t {
+			continue
+		}
+		h := s.suits[t.Suit]
+		for i, t2 := range h {
+			if t2 == t {
+				out = append(out, SolverDiscard{
+					Tile:  t,
+					Group: s.combine(t.Suit, s.solve(h.Remove(i))),
+				})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// combine joins the grouping of each suit, using g in place of the grouping of the
+// given suit. The zero Suit replaces nothing.
+func (s *Solver) combine(suit mj.Suit, g mj.Group) mj.Group {
+	var out mj.Group
+	add := func(g mj.Group) {
+		out.Pengs = append(out.Pengs, g.Pengs...)
+		out.Chis = append(out.Chis, g.Chis...)
+		out.Pairs = append(out.Pairs, g.Pairs...)
+		out.Free = append(out.Free, g.Free...)
+	}
+	for st, r := range s.results {
+		if st != suit {
+			add(r)
+		}
+	}
+	add(g)
+	return out.Copy(true)
+}
+
+// solve finds the optimal grouping of a sorted hand. The lowest tile in the hand is
+// either the first tile of a peng, pair or chi, or free.
+func (s *Solver) solve(h mj.Hand) mj.Group {
+	if len(h) == 0 {
+		return mj.Group{}
+	}
+	key := solverKey{hand: h.Marshal(), noChi: s.NoChi}
+	if g, ok := s.memo[key]; ok {
+		return g
+	}
+
+	t := h[0]
+	best := s.solve(h[1:])
+	best = mj.Group{Pengs: best.Pengs, Chis: best.Chis, Pairs: best.Pairs, Free: best.Free.Append(t)}
+	try := func(rest mj.Hand, add func(g *mj.Group)) {
+		r := s.solve(rest).Copy(false)
+		add(&r)
+		if r.Score() > best.Score() {
+			best = r
+		}
+	}
+
+	if rest, ok := h.TryPengAt(0); ok {
+		try(rest, func(g *mj.Group) { g.Pengs = append(g.Pengs, t) })
+	}
+	if rest, ok := h.TryPairAt(0); ok {
+		try(rest, func(g *mj.Group) { g.Pairs = append(g.Pairs, t) })
+	}
+	if !s.NoChi {
+		if rest, ok := h.TryChiAt(0); ok {
+			try(rest, func(g *mj.Group) { g.Chis = append(g.Chis, t) })
+		}
+	}
+
+	if s.memo == nil {
+		s.memo = make(map[solverKey]mj.Group)
+	}
+	best = best.Copy(true)
+	s.memo[key] = best
+	return best
+}
@@ -0,0 +1,91 @@
+package handcheck
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func Test_Solver(t *testing.T) {
+	var s Solver
+	s.Set(mj.MustParseHand("b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b4"))
+	s.Add(mj.MustParseHand("b5")[0])
+	if got, want := s.Group().Score(), 14; got != want {
+		t.Errorf("score after add = %d, want %d", got, want)
+	}
+	s.Add(mj.MustParseHand("b6")[0])
+	if got, want := s.Group().Score(), 18; got != want {
+		t.Errorf("score after second add = %d, want %d", got, want)
+	}
+	if !s.Remove(mj.MustParseHand("b1")[0]) {
+		t.Fatal("b1 not removed")
+	}
+	if s.Remove(mj.MustParseHand("b1")[0]) {
+		t.Error("removed b1 twice")
+	}
+	g := s.Group()
+	if len(g.Free) != 2 || len(g.Chis) != 3 || g.Pairs.String() != "🀄" {
+		t.Errorf("unexpected group after remove: %v", g)
+	}
+}
+
+func Test_Solver_NoChi(t *testing.T) {
+	var s Solver
+	s.Set(mj.MustParseHand("b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz"))
+	if got, want := s.Group().Score(), 14; got != want {
+		t.Errorf("score with chis = %d, want %d", got, want)
+	}
+	// the memo must not hand back the groupings that were found with chis
+	s.NoChi = true
+	s.Set(mj.MustParseHand("b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz"))
+	if g := s.Group(); len(g.Chis) != 0 || g.Score() != 2 {
+		t.Errorf("group without chis = %v, score %d", g, g.Score())
+	}
+}
+
+func Test_Solver_MatchesChecker(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, rs := range []mj.Ruleset{mj.HongKong, mj.Sanma} {
+		wall := rs.Wall(rng)
+		var hand mj.Hand
+		for _, tile := range wall {
+			if tile.CanMeld() {
+				hand = append(hand, tile)
+			}
+			if len(hand) == 14 {
+				break
+			}
+		}
+
+		s := Solver{NoChi: rs.NoChi}
+		s.Set(hand)
+		c := OptHandRLEChecker{UseMemo: true, NoChi: rs.NoChi}
+		if got, want := s.Group().Score(), c.Check(hand).Score(); got != want {
+			t.Errorf("%s: score = %d, want %d for %s", rs.Name, got, want, hand)
+		}
+
+		ds := s.Discards()
+		if len(ds) == 0 {
+			t.Fatalf("%s: no discards", rs.Name)
+		}
+		for _, d := range ds {
+			h := s.Hand()
+			for i := range h {
+				if h[i] == d.Tile {
+					h = h.Remove(i)
+					break
+				}
+			}
+			if got, want := d.Group.Score(), c.Check(h).Score(); got != want {
+				t.Errorf("%s: discard %s score = %d, want %d", rs.Name, d.Tile, got, want)
+			}
+			if got := len(d.Group.ToHand()); got != 13 {
+				t.Errorf("%s: discard %s leaves %d tiles", rs.Name, d.Tile, got)
+			}
+		}
+		if got := len(s.Hand()); got != 14 {
+			t.Errorf("%s: Discards changed the hand to %d tiles", rs.Name, got)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Package infer estimates what an opponent is holding from what they have shown: their
+// discards, calls and declared melds.
+package infer
+
+import (
+	"math/rand"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/danger"
+	"github.com/nik0sc/mj/handcheck"
+	"github.com/nik0sc/mj/special"
+	"github.com/nik0sc/mj/wait"
+)
+
+const (
+	defaultSamples = 500
+	// inconsistent is the weight given to a sample for each thing the opponent did that
+	// the sampled hand would not have done.
+	inconsistent = 0.25
+)
+
+// Estimate is what is known about an opponent's concealed hand.
+type Estimate struct {
+	// Tenpai is the chance that the opponent is waiting to win.
+	Tenpai float64
+	// Waits maps each tile to the chance that the opponent can win on it. A tile that
+	// the opponent discarded is never a wait, since they cannot win on it.
+	Waits map[mj.Tile]float64
+	// Samples is the number of hands that were sampled.
+	Samples int
+}
+
+// Sampler estimates opponents' hands by sampling concealed hands from the unseen tiles.
+//
+// Each sample is a random starting hand that is played forward for as many turns as
+// the opponent has discarded without calling, drawing from the unseen tiles and
+// discarding the tile that leaves the best grouping. Samples are then weighted by how
+// well they agree with what the opponent has shown:
+//  - A sample counts for less for each of the opponent's discards that would have
+//    brought it closer to winning, or completed it. The opponent kept the tiles in
+//    their hand over that discard, so they were probably no worse.
+//  - A sample counts for less if it holds the last copy of a declared peng, since the
+//    opponent would likely have added it to make a gang.
+//
+// The chance of waiting is the weighted share of samples that are waiting, and the
+// waits of each sample are taken over every way of grouping it, along with the special
+// hands that the Ruleset allows if the opponent has no melds.
+// The zero value is ready to use, and takes 500 samples.
+type Sampler struct {
+	// Rules gives the hand size and whether chis are allowed.
+	Rules mj.Ruleset
+	// Samples is the number of hands to sample. If 0, 500 hands are sampled.
+	Samples int
+	// Seed seeds the sampling.
+	Seed int64
+}
+
+// Infer estimates the opponent's hand. The unseen Counter holds the tiles that the
+// player has not seen, which are the tiles that may be in the opponent's hand.
+func (s Sampler) Infer(o danger.Opponent, unseen mj.Counter) Estimate {
+	samples := s.Samples
+	if samples == 0 {
+		samples = defaultSamples
+	}
+	size := s.Rules.Size() - 3*len(o.Melds)
+
+	var pool mj.Hand
+	for _, t := range unseen.ToHand(true) {
+		if t.CanMeld() {
+			pool = append(pool, t)
+		}
+	}
+	est := Estimate{Waits: make(map[mj.Tile]float64), Samples: samples}
+	if size < 1 || len(pool) < size {
+		est.Samples = 0
+		return est
+	}
+
+	turns := len(o.Discards) - len(o.Melds)
+	if turns > len(pool)-size {
+		turns = len(pool) - size
+	}
+
+	rng := rand.New(rand.NewSource(s.Seed))
+	solver := handcheck.Solver{NoChi: s.Rules.NoChi}
+	total, tenpai := 0.0, 0.0
+	waits := make(map[mj.Tile]float64)
+	for i := 0; i < samples; i++ {
+		hand := s.play(rng, &solver, pool, size, turns)
+		w := s.weight(hand, o)
+		total += w
+
+		ws := s.waits(hand, len(o.Melds) > 0)
+		if len(ws) == 0 {
+			continue
+		}
+		tenpai += w
+		for _, t := range ws {
+			if !contains(o.Discards, t) {
+				waits[t] += w
+			}
+		}
+	}
+
+	est.Tenpai = tenpai / total
+	for t, w := range waits {
+		est.Waits[t] = w / total
+	}
+	return est
+}
+
+// play deals a hand of size tiles from the pool and plays it for the given number of
+// turns, discarding the tile that leaves the highest scoring grouping. Ties are broken
+// at random.
+func (s Sampler) play(rng *rand.Rand, solver *handcheck.Solver, pool mj.Hand, size, turns int) mj.Hand {
+	perm := rng.Perm(len(pool))
+	hand := make(mj.Hand, size)
+	for i := range hand {
+		hand[i] = pool[perm[i]]
+	}
+	if turns <= 0 {
+		return hand
+	}
+
+	solver.Set(hand)
+	for i := 0; i < turns; i++ {
+		solver.Add(pool[perm[size+i]])
+		var best []mj.Tile
+		score := -1
+		for _, d := range solver.Discards() {
+			switch sc := d.Group.Score(); {
+			case sc > score:
+				best, score = []mj.Tile{d.Tile}, sc
+			case sc == score:
+				best = append(best, d.Tile)
+			}
+		}
+		solver.Remove(best[rng.Intn(len(best))])
+	}
+	return solver.Hand()
+}
+
+// weight returns the weight of a sampled hand, from how well it agrees with the
+// opponent's discards and melds.
+func (s Sampler) weight(hand mj.Hand, o danger.Opponent) float64 {
+	w := 1.0
+	shanten := handcheck.Shanten(hand, s.Rules)
+	for _, d := range o.Discards {
+		// with d added, the shanten is that of the best discard, so it only drops if
+		// keeping d would have been better than some tile in the hand
+		if handcheck.Shanten(hand.Append(d), s.Rules) < shanten {
+			w *= inconsistent
+		}
+	}
+
+	cnt := hand.ToCount()
+	for _, m := range o.Melds {
+		if m.Kind == mj.Peng && cnt.Get(m.Tile) > 0 {
+			w *= inconsistent
+		}
+	}
+	return w
+}
+
+// waits returns the tiles that a sampled hand waits on, over every grouping of it.
+func (s Sampler) waits(hand mj.Hand, melded bool) []mj.Tile {
+	found := make(map[mj.Tile]bool)
+	var out []mj.Tile
+	add := func(t mj.Tile) {
+		if !found[t] {
+			found[t] = true
+			out = append(out, t)
+		}
+	}
+	for _, w := range wait.Classify(hand, s.Rules) {
+		add(w.Tile)
+	}
+	if !melded {
+		for _, t := range special.Waits(hand, s.Rules) {
+			add(t)
+		}
+	}
+	return out
+}
+
+func contains(h mj.Hand, t mj.Tile) bool {
+	for _, t2 := range h {
+		if t2 == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package infer
+
+import (
+	"testing"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/danger"
+)
+
+func tile(s string) mj.Tile {
+	return mj.MustParseHand(s)[0]
+}
+
+func TestSampler_Infer(t *testing.T) {
+	unseen := mj.HongKong.NewCounterAtStart()
+	early := danger.Opponent{Discards: mj.MustParseHand("hn hw")}
+	late := danger.Opponent{
+		Discards:  mj.MustParseHand("hn hw b1 b9 c1 c9 w1 w9 he hs hz hf hb b2"),
+		Melds:     []mj.Meld{{Kind: mj.Peng, Tile: tile("c5")}},
+		CallTurns: []int{10},
+	}
+
+	s := Sampler{Samples: 200, Seed: 1}
+	e, l := s.Infer(early, unseen), s.Infer(late, unseen)
+	if e.Samples != 200 || l.Samples != 200 {
+		t.Errorf("samples = %d, %d, want 200", e.Samples, l.Samples)
+	}
+	if !(e.Tenpai < l.Tenpai) {
+		t.Errorf("tenpai early %v should be below late %v", e.Tenpai, l.Tenpai)
+	}
+	for w, p := range l.Waits {
+		if p <= 0 || p > l.Tenpai+1e-9 {
+			t.Errorf("wait %s has chance %v with tenpai %v", w, p, l.Tenpai)
+		}
+		for _, d := range late.Discards {
+			if w == d {
+				t.Errorf("discarded tile %s is a wait", w)
+			}
+		}
+	}
+
+	again := s.Infer(late, unseen)
+	if again.Tenpai != l.Tenpai || len(again.Waits) != len(l.Waits) {
+		t.Errorf("same seed gave %+v and %+v", again, l)
+	}
+}
+
+func TestSampler_InferBare(t *testing.T) {
+	// one concealed tile left: always waiting on it
+	o := danger.Opponent{
+		Discards: mj.MustParseHand("b1 b2 b3 b4"),
+		Melds: []mj.Meld{
+			{Kind: mj.Peng, Tile: tile("c1")}, {Kind: mj.Peng, Tile: tile("c2")},
+			{Kind: mj.Peng, Tile: tile("c3")}, {Kind: mj.Peng, Tile: tile("c4")},
+		},
+	}
+	e := Sampler{Samples: 50}.Infer(o, mj.MustParseHand("hz hz b1").ToCount())
+	if e.Tenpai != 1 {
+		t.Errorf("tenpai = %v, want 1", e.Tenpai)
+	}
+	if p := e.Waits[tile("hz")]; p < 0.5 {
+		t.Errorf("hz wait = %v, want most of the samples", p)
+	}
+	if _, ok := e.Waits[tile("b1")]; ok {
+		t.Error("discarded b1 is a wait")
+	}
+
+	if e := (Sampler{}).Infer(o, mj.Counter{}); e.Samples != 0 {
+		t.Errorf("sampled %d hands from no tiles", e.Samples)
+	}
+}
+
+func TestSampler_weight(t *testing.T) {
+	var s Sampler
+	// waiting on b3 or b6
+	hand := mj.MustParseHand("b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b4 b5")
+	tests := []struct {
+		name string
+		o    danger.Opponent
+		want float64
+	}{
+		{"unrelated discards", danger.Opponent{Discards: mj.MustParseHand("hn hw c9")}, 1},
+		{"discarded a wait", danger.Opponent{Discards: mj.MustParseHand("hn b6")}, inconsistent},
+		{"discarded both waits", danger.Opponent{Discards: mj.MustParseHand("b3 hn b6")}, inconsistent * inconsistent},
+		{"last copy of a peng", danger.Opponent{Melds: []mj.Meld{{Kind: mj.Peng, Tile: tile("hz")}}}, inconsistent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.weight(hand, tt.o); got != tt.want {
+				t.Errorf("weight() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package mj
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	Chi MeldKind = iota + 1
+	Peng
+	Gang
+)
+
+// MeldKind is the kind of a Meld. The zero MeldKind is invalid.
+type MeldKind byte
+
+// String returns the name of the MeldKind.
+func (k MeldKind) String() string {
+	switch k {
+	case Chi:
+		return "chi"
+	case Peng:
+		return "peng"
+	case Gang:
+		return "gang"
+	}
+	return fmt.Sprintf("MeldKind(%d)", byte(k))
+}
+
+// Meld is a declared set of tiles, such as a chi, peng or gang that was claimed from a
+// discard, or a concealed gang that was declared from the hand. Unlike Group, which is
+// only an interpretation of the free tiles in a hand, a Meld is fixed once declared.
+type Meld struct {
+	Kind MeldKind
+	// For a chi, this is the first of 3 consecutive tiles. Otherwise, this is the
+	// repeated tile.
+	Tile Tile
+	// Concealed is true if the meld was not formed by claiming a discard. Declared
+	// melds are only concealed if they are gangs, but scorers also use this to
+	// describe the melds that make up the concealed part of a hand.
+	Concealed bool
+}
+
+// Valid returns true if the Meld could be formed by some tiles.
+func (m Meld) Valid() bool {
+	if !m.Tile.CanMeld() {
+		return false
+	}
+
+	switch m.Kind {
+	case Chi:
+		return m.Tile.IsBasic() && m.Tile.Value <= 7
+	case Peng, Gang:
+		return true
+	}
+	return false
+}
+
+// Tiles returns all the tiles in the Meld, in sorted order.
+func (m Meld) Tiles() Hand {
+	switch m.Kind {
+	case Chi:
+		t2 := m.Tile
+		t2.Value++
+		t3 := t2
+		t3.Value++
+		return Hand{m.Tile, t2, t3}
+	case Peng:
+		return Hand{m.Tile, m.Tile, m.Tile}
+	case Gang:
+		return Hand{m.Tile, m.Tile, m.Tile, m.Tile}
+	}
+	return nil
+}
+
+// Contains returns true if the tile is part of the Meld.
+func (m Meld) Contains(t Tile) bool {
+	if m.Kind == Chi {
+		return t.Suit == m.Tile.Suit && m.Tile.Value <= t.Value && t.Value <= m.Tile.Value+2
+	}
+	return t == m.Tile
+}
+
+// String returns the human-readable representation of this Meld. Concealed gangs
+// are shown with the outer tiles face down.
+func (m Meld) String() string {
+	if m.Kind == Gang && m.Concealed {
+		back := Tile{}.String()
+		return back + strings.Repeat(m.Tile.String(), 2) + back
+	}
+	return m.Tiles().String()
+}
@@ -0,0 +1,533 @@
+package mjai
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+	"github.com/nik0sc/mj/score"
+)
+
+// State is what the bot knows of the game.
+type State struct {
+	// Seat is the bot's seat.
+	Seat int
+	// Names holds the name of the player in each seat.
+	Names []string
+	// Rules is mj.Riichi.
+	Rules mj.Ruleset
+	// Round is the prevailing wind.
+	Round mj.Value
+	// Dealer is the seat of the dealer.
+	Dealer int
+	// Hand holds the bot's concealed tiles, sorted.
+	Hand mj.Hand
+	// Drawn is the tile the bot has just drawn, or the zero Tile if it is not the bot's
+	// turn or the bot called a discard.
+	Drawn mj.Tile
+	// Melds holds each seat's melds.
+	Melds [][]mj.Meld
+	// Discards holds each seat's discards, including discards that were called.
+	Discards []mj.Hand
+	// Riichi is true for each seat that has declared riichi.
+	Riichi []bool
+	// Dora holds the dora indicators.
+	Dora mj.Hand
+	// Visible holds the tiles the bot has seen.
+	Visible mj.Visible
+
+	// red counts the red fives in the hand.
+	red map[mj.Tile]int
+}
+
+// SeatWind returns the wind of a seat in the current round. With three players, the
+// seat before the dealer is West and nobody is North.
+func (s *State) SeatWind(seat int) mj.Value {
+	n := s.Rules.Players()
+	return mj.East + mj.Value((seat-s.Dealer+n)%n)
+}
+
+// RedFives returns the number of red fives in the bot's hand.
+func (s *State) RedFives() int {
+	n := 0
+	for _, c := range s.red {
+		n += c
+	}
+	return n
+}
+
+// CanWin reports whether the bot's hand wins with the tile, and has a yaku. If the tile
+// was not drawn, the Hand does not hold it.
+func (s *State) CanWin(tile mj.Tile, red, selfDrawn bool) bool {
+	concealed := s.Hand
+	reds := s.RedFives()
+	if !selfDrawn {
+		concealed = concealed.Append(tile)
+		if red {
+			reds++
+		}
+	}
+	if len(concealed)%3 != 2 || handcheck.Shanten(concealed, s.Rules) != -1 {
+		return false
+	}
+	_, err := score.RiichiScorer{}.Score(score.Win{
+		Concealed:      concealed,
+		Melds:          s.Melds[s.Seat],
+		WinningTile:    tile,
+		SelfDrawn:      selfDrawn,
+		Seat:           s.SeatWind(s.Seat),
+		Round:          s.Round,
+		Rules:          s.Rules,
+		Riichi:         s.Riichi[s.Seat],
+		DoraIndicators: s.Dora,
+		RedFives:       reds,
+	})
+	return err == nil
+}
+
+// Furiten reports whether the bot is waiting on a tile that it has discarded, which
+// forbids it from winning on a discard.
+func (s *State) Furiten() bool {
+	if len(s.Hand)%3 != 1 {
+		return false
+	}
+	for _, t := range s.Discards[s.Seat] {
+		if handcheck.Shanten(s.Hand.Append(t), s.Rules) == -1 {
+			return true
+		}
+	}
+	return false
+}
+
+// add adds a tile to the hand.
+func (s *State) add(t mj.Tile, red bool) {
+	s.Hand = s.Hand.Append(t)
+	sort.Sort(s.Hand)
+	if red {
+		s.red[t]++
+	}
+	s.Visible.SetHand(s.Hand)
+}
+
+// take removes a tile from the hand, and returns false if it is not there.
+func (s *State) take(t mj.Tile, red bool) bool {
+	for i, ht := range s.Hand {
+		if ht != t {
+			continue
+		}
+		if red {
+			if s.red[t] == 0 {
+				return false
+			}
+			s.red[t]--
+		} else if s.red[t] == s.Hand.ToCount().Get(t) {
+			// only red copies are left
+			return false
+		}
+		s.Hand = s.Hand.Remove(i)
+		s.Visible.SetHand(s.Hand)
+		return true
+	}
+	return false
+}
+
+// discards returns a dahai for each distinct tile in the hand, with red and plain fives
+// kept apart.
+func (s *State) discards() []Message {
+	var out []Message
+	cnt := s.Hand.ToCount()
+	for i, t := range s.Hand {
+		if i > 0 && s.Hand[i-1] == t {
+			continue
+		}
+		if s.red[t] > 0 {
+			out = append(out, s.dahai(t, true))
+		}
+		if cnt.Get(t) > s.red[t] {
+			out = append(out, s.dahai(t, false))
+		}
+	}
+	return out
+}
+
+func (s *State) dahai(t mj.Tile, red bool) Message {
+	return Message{Type: "dahai", Actor: s.Seat, Pai: FormatTile(t, red), Tsumogiri: s.Drawn == t}
+}
+
+// Decider chooses the bot's response to a message from the server. The options hold
+// every response the bot may make, and there is always at least one. Decide must return
+// one of the options.
+type Decider interface {
+	Decide(s *State, msg Message, options []Message) Message
+}
+
+// DeciderFunc adapts a function to the Decider interface.
+type DeciderFunc func(s *State, msg Message, options []Message) Message
+
+// Decide calls f.
+func (f DeciderFunc) Decide(s *State, msg Message, options []Message) Message {
+	return f(s, msg, options)
+}
+
+// Bot plays a game through mjai. It answers every message from the server, asking its
+// Decider when there is more than one thing it may do.
+//
+// The bot may win, declare riichi, discard, and call chi and pon. It never declares a
+// kan. After a call it does not discard the called tile, or the tile at the other end of
+// a chi that was called on one end (kuikae), unless it has nothing else to discard. Calls
+// are not checked for whether they leave such a discard.
+type Bot struct {
+	// Name is sent to the server when joining. If empty, "mj" is sent.
+	Name string
+	// Room is sent to the server when joining. If empty, "default" is sent.
+	Room string
+	// Decider makes the bot's choices. If nil, Efficient is used.
+	Decider Decider
+
+	state State
+}
+
+// State returns what the bot knows of the game.
+func (b *Bot) State() *State {
+	return &b.state
+}
+
+// Run answers the messages read from r, writing each answer to w on its own line, until
+// the game ends or r is closed.
+func (b *Bot) Run(r io.Reader, w io.Writer) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	enc := json.NewEncoder(w)
+	for sc.Scan() {
+		var msg Message
+		if err := json.Unmarshal(sc.Bytes(), &msg); err != nil {
+			return fmt.Errorf("%w: %v", ErrProtocol, err)
+		}
+		resp, err := b.Respond(msg)
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+		if msg.Type == "end_game" {
+			return nil
+		}
+	}
+	return sc.Err()
+}
+
+// Respond updates the state with a message from the server and returns the answer.
+func (b *Bot) Respond(msg Message) (Message, error) {
+	options, err := b.update(msg)
+	if err != nil {
+		return Message{}, fmt.Errorf("%s: %w", msg.Type, err)
+	}
+	if len(options) == 0 {
+		if msg.Type == "hello" {
+			return b.join(), nil
+		}
+		return Message{Type: "none"}, nil
+	}
+	if len(options) == 1 {
+		return options[0], nil
+	}
+
+	d := b.Decider
+	if d == nil {
+		d = Efficient{}
+	}
+	resp := d.Decide(&b.state, msg, options)
+	for _, o := range options {
+		if o.Type == resp.Type && o.Pai == resp.Pai && equal(o.Consumed, resp.Consumed) {
+			return o, nil
+		}
+	}
+	return Message{}, fmt.Errorf("decider chose %+v, which is not an option", resp)
+}
+
+func (b *Bot) join() Message {
+	m := Message{Type: "join", Name: b.Name, Room: b.Room}
+	if m.Name == "" {
+		m.Name = "mj"
+	}
+	if m.Room == "" {
+		m.Room = "default"
+	}
+	return m
+}
+
+// update applies the message to the state, and returns the bot's options, if it has any
+// besides doing nothing.
+func (b *Bot) update(msg Message) ([]Message, error) {
+	s := &b.state
+	if s.Melds == nil && msg.Type != "hello" && msg.Type != "start_game" && msg.Type != "start_kyoku" &&
+		msg.Type != "end_game" && msg.Type != "error" {
+		return nil, fmt.Errorf("%w: no round has started", ErrProtocol)
+	}
+	me := msg.Actor == s.Seat
+
+	switch msg.Type {
+	case "start_game":
+		b.state = State{Seat: msg.ID, Names: msg.Names, Rules: mj.Riichi}
+	case "start_kyoku":
+		return nil, b.startKyoku(msg)
+	case "tsumo":
+		if !me {
+			break
+		}
+		t, red, err := ParseTile(msg.Pai)
+		if err != nil {
+			return nil, err
+		}
+		s.add(t, red)
+		s.Drawn = t
+		return b.turnOptions(t, red), nil
+	case "dahai":
+		t, red, err := ParseTile(msg.Pai)
+		if err != nil {
+			return nil, err
+		}
+		s.Discards[msg.Actor] = append(s.Discards[msg.Actor], t)
+		s.Visible.Discard(t)
+		if me {
+			if !s.take(t, red) {
+				return nil, fmt.Errorf("%w: discarded %s, which is not in the hand", ErrProtocol, msg.Pai)
+			}
+			s.Drawn = mj.Tile{}
+			return nil, nil
+		}
+		return b.callOptions(msg, t, red), nil
+	case "chi", "pon", "daiminkan", "ankan", "kakan":
+		return b.meld(msg)
+	case "reach":
+		if me {
+			// riichi is declared with the next discard, which must leave the hand waiting
+			var out []Message
+			for _, d := range s.discards() {
+				t, _, _ := ParseTile(d.Pai)
+				i := index(s.Hand, t)
+				if i >= 0 && handcheck.Shanten(s.Hand.Remove(i), s.Rules) == 0 {
+					out = append(out, d)
+				}
+			}
+			return out, nil
+		}
+	case "reach_accepted":
+		s.Riichi[msg.Actor] = true
+	case "dora":
+		t, _, err := ParseTile(msg.DoraMarker)
+		if err != nil {
+			return nil, err
+		}
+		s.Dora = append(s.Dora, t)
+	case "error":
+		return nil, fmt.Errorf("%w: server error: %s", ErrProtocol, msg.Message)
+	}
+	return nil, nil
+}
+
+func (b *Bot) startKyoku(msg Message) error {
+	s := &b.state
+	round, _, err := ParseTile(msg.Bakaze)
+	if err != nil || round.Suit != mj.Honour {
+		return fmt.Errorf("%w: bad bakaze %q", ErrProtocol, msg.Bakaze)
+	}
+	dora, _, err := ParseTile(msg.DoraMarker)
+	if err != nil {
+		return err
+	}
+	if s.Seat >= len(msg.Tehais) {
+		return fmt.Errorf("%w: no hand for seat %d", ErrProtocol, s.Seat)
+	}
+	n := len(msg.Tehais)
+	s.Rules = mj.Riichi
+	if n == 3 {
+		s.Rules = mj.Sanma
+	}
+	s.Round, s.Dealer, s.Dora = round.Value, msg.Oya, mj.Hand{dora}
+	s.Melds, s.Discards, s.Riichi = make([][]mj.Meld, n), make([]mj.Hand, n), make([]bool, n)
+	s.Visible = mj.Visible{Rules: s.Rules}
+	s.Hand, s.Drawn, s.red = nil, mj.Tile{}, make(map[mj.Tile]int)
+	for _, ts := range msg.Tehais[s.Seat] {
+		t, red, err := ParseTile(ts)
+		if err != nil {
+			return err
+		}
+		s.add(t, red)
+	}
+	return nil
+}
+
+// turnOptions returns what the bot may do after drawing a tile.
+func (b *Bot) turnOptions(t mj.Tile, red bool) []Message {
+	s := &b.state
+	var out []Message
+	if s.CanWin(t, red, true) {
+		out = append(out, Message{Type: "hora", Actor: s.Seat, Target: s.Seat, Pai: FormatTile(t, red)})
+	}
+	if s.Riichi[s.Seat] {
+		return append(out, Message{Type: "dahai", Actor: s.Seat, Pai: FormatTile(t, red), Tsumogiri: true})
+	}
+
+	closed := true
+	for _, m := range s.Melds[s.Seat] {
+		closed = closed && m.Concealed
+	}
+	if closed && handcheck.Shanten(s.Hand, s.Rules) <= 0 {
+		out = append(out, Message{Type: "reach", Actor: s.Seat})
+	}
+	return append(out, s.discards()...)
+}
+
+// callOptions returns what the bot may do when another seat discards.
+func (b *Bot) callOptions(msg Message, t mj.Tile, red bool) []Message {
+	s := &b.state
+	var out []Message
+	if s.CanWin(t, red, false) && !s.Furiten() {
+		out = append(out, Message{Type: "hora", Actor: s.Seat, Target: msg.Actor, Pai: msg.Pai})
+	}
+	if s.Riichi[s.Seat] || len(s.Hand) < 4 {
+		return addNone(out)
+	}
+
+	if s.Hand.ToCount().Get(t) >= 2 {
+		out = append(out, Message{Type: "pon", Actor: s.Seat, Target: msg.Actor, Pai: msg.Pai,
+			Consumed: b.consume(t, t)})
+	}
+	if !s.Rules.NoChi && t.IsBasic() && msg.Actor == (s.Seat+len(s.Melds)-1)%len(s.Melds) {
+		cnt := s.Hand.ToCount()
+		for v := int(t.Value) - 2; v <= int(t.Value); v++ {
+			if v < 1 || v+2 > 9 {
+				continue
+			}
+			var need mj.Hand
+			for w := v; w < v+3; w++ {
+				if w != int(t.Value) {
+					need = append(need, mj.Tile{Suit: t.Suit, Value: mj.Value(w)})
+				}
+			}
+			if cnt.Get(need[0]) > 0 && cnt.Get(need[1]) > 0 {
+				out = append(out, Message{Type: "chi", Actor: s.Seat, Target: msg.Actor, Pai: msg.Pai,
+					Consumed: b.consume(need[0], need[1])})
+			}
+		}
+	}
+	return addNone(out)
+}
+
+// consume returns the tiles of the hand used in a call, using plain fives before red
+// ones.
+func (b *Bot) consume(ts ...mj.Tile) []string {
+	s := &b.state
+	used := make(map[mj.Tile]int)
+	out := make([]string, len(ts))
+	for i, t := range ts {
+		plain := s.Hand.ToCount().Get(t) - s.red[t]
+		out[i] = FormatTile(t, used[t] >= plain)
+		used[t]++
+	}
+	return out
+}
+
+// meld applies a call by any seat, and returns the bot's discards if it made the call.
+func (b *Bot) meld(msg Message) ([]Message, error) {
+	s := &b.state
+	m, err := msg.Meld()
+	if err != nil {
+		return nil, err
+	}
+	var claimed mj.Tile
+	switch msg.Type {
+	case "chi", "pon", "daiminkan":
+		claimed, _, _ = ParseTile(msg.Pai)
+	}
+
+	if msg.Type == "kakan" {
+		for i, pm := range s.Melds[msg.Actor] {
+			if pm.Kind == mj.Peng && pm.Tile == m.Tile {
+				s.Melds[msg.Actor][i] = m
+			}
+		}
+		// only the added tile is new
+		t, _, _ := ParseTile(msg.Pai)
+		s.Visible.Discard(t)
+	} else {
+		s.Melds[msg.Actor] = append(s.Melds[msg.Actor], m)
+		s.Visible.Meld(m, claimed)
+	}
+
+	if msg.Actor != s.Seat {
+		return nil, nil
+	}
+	taken := msg.Consumed
+	if msg.Type == "kakan" {
+		taken = []string{msg.Pai}
+	}
+	for _, ts := range taken {
+		t, red, err := ParseTile(ts)
+		if err != nil {
+			return nil, err
+		}
+		if !s.take(t, red) {
+			return nil, fmt.Errorf("%w: called with %s, which is not in the hand", ErrProtocol, ts)
+		}
+	}
+	s.Drawn = mj.Tile{}
+	if msg.Type != "chi" && msg.Type != "pon" {
+		// a kan is followed by a replacement draw
+		return nil, nil
+	}
+	// the tile across a chi from the called tile, if it was called on one end
+	var suji mj.Tile
+	if msg.Type == "chi" {
+		switch claimed.Value {
+		case m.Tile.Value:
+			suji = mj.Tile{Suit: claimed.Suit, Value: claimed.Value + 3}
+		case m.Tile.Value + 2:
+			suji = mj.Tile{Suit: claimed.Suit, Value: claimed.Value - 3}
+		}
+	}
+	var out []Message
+	for _, d := range s.discards() {
+		if t, _, _ := ParseTile(d.Pai); t != claimed && (!suji.Valid() || t != suji) {
+			out = append(out, d)
+		}
+	}
+	if len(out) == 0 {
+		out = s.discards()
+	}
+	return out, nil
+}
+
+func addNone(options []Message) []Message {
+	if len(options) == 0 {
+		return nil
+	}
+	return append(options, Message{Type: "none"})
+}
+
+func index(h mj.Hand, t mj.Tile) int {
+	for i, ht := range h {
+		if ht == t {
+			return i
+		}
+	}
+	return -1
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
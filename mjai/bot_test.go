@@ -0,0 +1,243 @@
+package mjai
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+// server stands in for an mjai server, sending one message at a time to a bot and
+// reading its answer.
+type server struct {
+	t    *testing.T
+	in   *io.PipeWriter
+	out  *json.Decoder
+	done chan error
+}
+
+func serve(t *testing.T, b *Bot) *server {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	s := &server{t: t, in: inW, out: json.NewDecoder(outR), done: make(chan error, 1)}
+	go func() {
+		err := b.Run(inR, outW)
+		outW.Close()
+		s.done <- err
+	}()
+	return s
+}
+
+// send sends a message and returns the bot's answer.
+func (s *server) send(m Message) Message {
+	s.t.Helper()
+	b, err := json.Marshal(m)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	if _, err := s.in.Write(append(b, '\n')); err != nil {
+		s.t.Fatalf("%s: %v", m.Type, err)
+	}
+	var resp Message
+	if err := s.out.Decode(&resp); err != nil {
+		s.t.Fatalf("%s: %v (bot: %v)", m.Type, err, <-s.done)
+	}
+	return resp
+}
+
+// expect sends a message and checks the type and tile of the answer.
+func (s *server) expect(m Message, typ, pai string) Message {
+	s.t.Helper()
+	resp := s.send(m)
+	if resp.Type != typ || pai != "" && resp.Pai != pai {
+		s.t.Fatalf("%s %s: bot answered %+v, want %s %s", m.Type, m.Pai, resp, typ, pai)
+	}
+	return resp
+}
+
+// end ends the game and checks that the bot stops.
+func (s *server) end() {
+	s.t.Helper()
+	s.expect(Message{Type: "end_game"}, "none", "")
+	if err := <-s.done; err != nil {
+		s.t.Fatal(err)
+	}
+}
+
+func tehais(seat int, hand string) [][]string {
+	out := make([][]string, 4)
+	for i := range out {
+		if i == seat {
+			out[i] = strings.Fields(hand)
+		} else {
+			out[i] = strings.Fields(strings.Repeat("? ", 13))
+		}
+	}
+	return out
+}
+
+func kyoku(seat int, hand string) Message {
+	return Message{Type: "start_kyoku", Bakaze: "E", Kyoku: 1, Oya: 0, DoraMarker: "9p", Tehais: tehais(seat, hand)}
+}
+
+func TestBot_Ron(t *testing.T) {
+	s := serve(t, &Bot{Name: "test"})
+	join := s.expect(Message{Type: "hello", Protocol: "mjsonp", ProtocolVersion: 3}, "join", "")
+	if join.Name != "test" || join.Room != "default" {
+		t.Errorf("join %+v", join)
+	}
+	s.expect(Message{Type: "start_game", ID: 1, Names: []string{"a", "b", "c", "d"}}, "none", "")
+	s.expect(kyoku(1, "1m 2m 3m 4p 5p 6p 7s 8s 9s E E P P"), "none", "")
+	s.expect(Message{Type: "tsumo", Actor: 0, Pai: "?"}, "none", "")
+	// white dragon is a yaku
+	hora := s.expect(Message{Type: "dahai", Actor: 0, Pai: "P", Tsumogiri: true}, "hora", "P")
+	if hora.Actor != 1 || hora.Target != 0 {
+		t.Errorf("hora %+v", hora)
+	}
+	s.end()
+}
+
+func TestBot_Riichi(t *testing.T) {
+	s := serve(t, &Bot{})
+	s.expect(Message{Type: "start_game", ID: 0}, "none", "")
+	s.expect(kyoku(0, "1m 2m 3m 4p 5p 6p 7s 8s 9s 1s 1s E 5mr"), "none", "")
+	s.expect(Message{Type: "tsumo", Actor: 0, Pai: "E"}, "reach", "")
+	// only the red five keeps the hand waiting
+	d := s.expect(Message{Type: "reach", Actor: 0}, "dahai", "5mr")
+	if d.Tsumogiri {
+		t.Errorf("dahai %+v is not of the drawn tile", d)
+	}
+	s.expect(d, "none", "")
+	s.expect(Message{Type: "reach_accepted", Actor: 0}, "none", "")
+	// after riichi, the drawn tile is discarded
+	d = s.expect(Message{Type: "tsumo", Actor: 0, Pai: "9m"}, "dahai", "9m")
+	if !d.Tsumogiri {
+		t.Errorf("dahai %+v should be of the drawn tile", d)
+	}
+	s.expect(d, "none", "")
+	s.expect(Message{Type: "tsumo", Actor: 1, Pai: "?"}, "none", "")
+	s.expect(Message{Type: "dahai", Actor: 1, Pai: "9m"}, "none", "")
+	s.expect(Message{Type: "tsumo", Actor: 3, Pai: "?"}, "none", "")
+	hora := s.expect(Message{Type: "dahai", Actor: 3, Pai: "E"}, "hora", "E")
+	if hora.Target != 3 {
+		t.Errorf("hora %+v", hora)
+	}
+	s.end()
+}
+
+func TestBot_Chi(t *testing.T) {
+	var offered []Message
+	b := &Bot{Decider: DeciderFunc(func(st *State, msg Message, options []Message) Message {
+		if msg.Type == "dahai" {
+			offered = options
+			for _, o := range options {
+				if o.Type == "chi" && equal(o.Consumed, []string{"4m", "6m"}) {
+					return o
+				}
+			}
+		}
+		return Efficient{}.Decide(st, msg, options)
+	})}
+	s := serve(t, b)
+	s.expect(Message{Type: "start_game", ID: 1}, "none", "")
+	s.expect(kyoku(1, "3m 4m 6m 7p 8p 9p 1s 2s 3s S S N W"), "none", "")
+	s.expect(Message{Type: "tsumo", Actor: 0, Pai: "?"}, "none", "")
+	chi := s.expect(Message{Type: "dahai", Actor: 0, Pai: "5m"}, "chi", "5m")
+	if len(offered) != 3 || chi.Target != 0 {
+		t.Errorf("offered %+v, chose %+v", offered, chi)
+	}
+	d := s.expect(chi, "dahai", "")
+	if d.Pai == "5m" || d.Actor != 1 {
+		t.Errorf("discarded %+v after chi", d)
+	}
+	if ms := b.State().Melds[1]; len(ms) != 1 || ms[0].Kind != mj.Chi || mj.FormatTile(ms[0].Tile) != "w4" ||
+		len(b.State().Hand) != 11 {
+		t.Errorf("melds %v, hand %v", ms, b.State().Hand)
+	}
+	s.expect(d, "none", "")
+	// a discard from the seat across cannot be called with a chi
+	s.expect(Message{Type: "dahai", Actor: 3, Pai: "2m"}, "none", "")
+	s.end()
+}
+
+func TestBot_Kuikae(t *testing.T) {
+	var offered []Message
+	b := &Bot{Decider: DeciderFunc(func(st *State, msg Message, options []Message) Message {
+		switch msg.Type {
+		case "dahai":
+			for _, o := range options {
+				if o.Type == "chi" {
+					return o
+				}
+			}
+		case "chi":
+			offered = options
+		}
+		return Efficient{}.Decide(st, msg, options)
+	})}
+	s := serve(t, b)
+	s.expect(Message{Type: "start_game", ID: 1}, "none", "")
+	s.expect(kyoku(1, "2m 3m 4m 5m 7p 8p 9p 1s 2s 3s S S N"), "none", "")
+	s.expect(Message{Type: "tsumo", Actor: 0, Pai: "?"}, "none", "")
+	chi := s.expect(Message{Type: "dahai", Actor: 0, Pai: "1m"}, "chi", "1m")
+	if !equal(chi.Consumed, []string{"2m", "3m"}) {
+		t.Fatalf("chose %+v", chi)
+	}
+	d := s.expect(chi, "dahai", "")
+	for _, o := range offered {
+		// 1m was called with 2m 3m, so 4m may not be discarded either
+		if o.Pai == "1m" || o.Pai == "4m" {
+			t.Errorf("offered %+v after chi", o)
+		}
+	}
+	if len(offered) != 9 {
+		t.Errorf("offered %d discards, want 9: %+v", len(offered), offered)
+	}
+	s.expect(d, "none", "")
+	s.end()
+}
+
+func TestBot_Errors(t *testing.T) {
+	var b Bot
+	if _, err := b.Respond(Message{Type: "tsumo", Actor: 0, Pai: "1m"}); err == nil {
+		t.Error("tsumo before start_kyoku should fail")
+	}
+	b.Respond(Message{Type: "start_game", ID: 0})
+	b.Respond(kyoku(0, "1m 2m 3m 4p 5p 6p 7s 8s 9s 1s 1s E 5mr"))
+	if _, err := b.Respond(Message{Type: "dahai", Actor: 0, Pai: "5m"}); err == nil {
+		t.Error("discarding a plain 5m when only the red one is held should fail")
+	}
+	b.Decider = DeciderFunc(func(*State, Message, []Message) Message { return Message{Type: "dahai", Pai: "C"} })
+	if _, err := b.Respond(Message{Type: "tsumo", Actor: 0, Pai: "2s"}); err == nil {
+		t.Error("a response that was not offered should fail")
+	}
+	if _, err := b.Respond(Message{Type: "error", Message: "bad"}); err == nil {
+		t.Error("error message should fail")
+	}
+}
+
+func TestState_SeatWind(t *testing.T) {
+	for _, c := range []struct {
+		players int
+		want    []mj.Value
+	}{
+		{4, []mj.Value{mj.North, mj.East, mj.South, mj.West}},
+		{3, []mj.Value{mj.West, mj.East, mj.South}},
+	} {
+		var b Bot
+		b.Respond(Message{Type: "start_game", ID: 1})
+		msg := kyoku(1, "1m 9m 1p 2p 3p 4p 5p 6p 7p 8p 9p E E")
+		msg.Oya, msg.Tehais = 1, msg.Tehais[:c.players]
+		if _, err := b.Respond(msg); err != nil {
+			t.Fatal(err)
+		}
+		st := b.State()
+		for seat, want := range c.want {
+			if got := st.SeatWind(seat); got != want {
+				t.Errorf("%d players: SeatWind(%d) = %v, want %v", c.players, seat, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package mjai
+
+import (
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+	"github.com/nik0sc/mj/ukeire"
+	"github.com/nik0sc/mj/wait"
+)
+
+// Efficient is a Decider that plays for tile efficiency:
+//  - It always wins when it can.
+//  - It discards the tile that leaves the lowest shanten. Among those, a waiting hand
+//    keeps the wait with the most unseen winning tiles, found with wait.Classify, and
+//    other hands keep the most unseen tiles that would improve them. Red fives are
+//    kept when there is a choice.
+//  - It declares riichi whenever it can.
+//  - It calls a pon or chi only if the call lowers its shanten, and either its hand is
+//    already open or the call is a pon of a dragon or a wind worth a yaku, so that the
+//    open hand can still win.
+// The zero value is ready to use.
+type Efficient struct{}
+
+// Decide implements Decider.
+func (Efficient) Decide(s *State, msg Message, options []Message) Message {
+	var dahai []Message
+	for _, o := range options {
+		switch o.Type {
+		case "hora", "reach":
+			return o
+		case "dahai":
+			dahai = append(dahai, o)
+		}
+	}
+	if len(dahai) > 0 {
+		return bestDiscard(s, s.Hand, dahai)
+	}
+
+	t, _, _ := ParseTile(msg.Pai)
+	before := handcheck.Shanten(s.Hand, s.Rules)
+	for _, o := range options {
+		if o.Type != "pon" && o.Type != "chi" {
+			continue
+		}
+		m, err := o.Meld()
+		if err != nil || !yakuCall(s, m) {
+			continue
+		}
+		hand := s.Hand
+		claimed := t
+		for _, mt := range m.Tiles() {
+			if mt == claimed {
+				claimed = mj.Tile{}
+			} else {
+				hand = hand.Remove(index(hand, mt))
+			}
+		}
+		if bestShanten(hand, s.Rules) < before {
+			return o
+		}
+	}
+	return Message{Type: "none"}
+}
+
+// yakuCall reports whether the hand can still have a yaku after the call.
+func yakuCall(s *State, m mj.Meld) bool {
+	for _, pm := range s.Melds[s.Seat] {
+		if !pm.Concealed {
+			return true
+		}
+	}
+	if m.Kind != mj.Peng || m.Tile.Suit != mj.Honour {
+		return false
+	}
+	v := m.Tile.Value
+	return v == mj.Zhong || v == mj.Fa || v == mj.Ban || v == s.Round || v == s.SeatWind(s.Seat)
+}
+
+// bestShanten returns the lowest shanten after discarding from a hand with 3n+2 tiles.
+func bestShanten(hand mj.Hand, rs mj.Ruleset) int {
+	best := -1
+	for i := range hand {
+		if s := handcheck.Shanten(hand.Remove(i), rs); i == 0 || s < best {
+			best = s
+		}
+	}
+	return best
+}
+
+// bestDiscard returns the dahai option that leaves the best hand.
+func bestDiscard(s *State, hand mj.Hand, options []Message) Message {
+	var best Message
+	bestShanten, bestCount := 0, 0
+	bestRed := false
+	for i, o := range options {
+		t, red, _ := ParseTile(o.Pai)
+		left := hand.Remove(index(hand, t))
+		sh := handcheck.Shanten(left, s.Rules)
+		var count int
+		if sh == 0 {
+			count = winningTiles(s, left)
+		} else {
+			count = ukeire.Accept(left, s.Visible.Public(), s.Rules).Count
+		}
+		if i == 0 || sh < bestShanten || sh == bestShanten && (count > bestCount ||
+			count == bestCount && bestRed && !red) {
+			best, bestShanten, bestCount, bestRed = o, sh, count, red
+		}
+	}
+	return best
+}
+
+// winningTiles counts the unseen copies of the tiles that a waiting hand wins on.
+func winningTiles(s *State, hand mj.Hand) int {
+	n := 0
+	seen := make(map[mj.Tile]bool)
+	for _, w := range wait.Classify(hand, s.Rules) {
+		if !seen[w.Tile] {
+			seen[w.Tile] = true
+			n += s.Visible.Unseen(w.Tile)
+		}
+	}
+	if n == 0 {
+		// special hands are not classified, so count them by acceptance
+		return ukeire.Accept(hand, s.Visible.Public(), s.Rules).Count
+	}
+	return n
+}
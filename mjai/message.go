@@ -0,0 +1,189 @@
+// Package mjai lets bots play through mjai, the JSON protocol spoken by many mahjong AIs
+// and the harnesses that pit them against each other.
+//
+// In mjai, the server sends the bot one JSON object per line for every event of the game,
+// such as a tsumo (draw) or a dahai (discard), and the bot answers each with one line:
+// an action such as a discard or a call, or a message of type "none". Tiles are written
+// as "1m" to "9m" for characters, "p" for dots and "s" for bamboo, "5mr" and so on for
+// red fives, and "E", "S", "W", "N", "P", "F" and "C" for the winds and the white, green
+// and red dragons. Seats are numbered from 0 to 3 in the order of the first round.
+//
+// Message and the tile functions translate between mjai and the types of this module,
+// and Bot answers a server on a reader and a writer, asking a Decider for each action.
+package mjai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nik0sc/mj"
+)
+
+// ErrProtocol is returned when the server sends a message that does not fit the game.
+var ErrProtocol = errors.New("mjai protocol error")
+
+// Message is an mjai message. Only the fields used by its Type are sent.
+type Message struct {
+	Type string `json:"type"`
+
+	Actor     int      `json:"actor"`
+	Target    int      `json:"target"`
+	Pai       string   `json:"pai"`
+	Consumed  []string `json:"consumed"`
+	Tsumogiri bool     `json:"tsumogiri"`
+
+	// start_game
+	ID    int      `json:"id"`
+	Names []string `json:"names"`
+
+	// start_kyoku
+	Bakaze     string     `json:"bakaze"`
+	Kyoku      int        `json:"kyoku"`
+	Honba      int        `json:"honba"`
+	Kyotaku    int        `json:"kyotaku"`
+	Oya        int        `json:"oya"`
+	DoraMarker string     `json:"dora_marker"`
+	Tehais     [][]string `json:"tehais"`
+
+	Scores []int `json:"scores"`
+	Deltas []int `json:"deltas"`
+
+	// hello and join
+	Protocol        string `json:"protocol"`
+	ProtocolVersion int    `json:"protocol_version"`
+	Name            string `json:"name"`
+	Room            string `json:"room"`
+
+	// error
+	Message string `json:"message"`
+}
+
+// fields lists the fields sent for each type of message, besides the type.
+var fields = map[string][]string{
+	"hello":          {"protocol", "protocol_version"},
+	"join":           {"name", "room"},
+	"start_game":     {"id", "names"},
+	"start_kyoku":    {"bakaze", "kyoku", "honba", "kyotaku", "oya", "dora_marker", "tehais"},
+	"tsumo":          {"actor", "pai"},
+	"dahai":          {"actor", "pai", "tsumogiri"},
+	"chi":            {"actor", "target", "pai", "consumed"},
+	"pon":            {"actor", "target", "pai", "consumed"},
+	"daiminkan":      {"actor", "target", "pai", "consumed"},
+	"ankan":          {"actor", "consumed"},
+	"kakan":          {"actor", "pai", "consumed"},
+	"reach":          {"actor"},
+	"reach_accepted": {"actor", "deltas", "scores"},
+	"dora":           {"dora_marker"},
+	"hora":           {"actor", "target", "pai", "deltas", "scores"},
+	"ryukyoku":       {"deltas", "scores"},
+	"end_game":       {"scores"},
+	"error":          {"message"},
+}
+
+// MarshalJSON writes the type and the fields used by the type.
+func (m Message) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{"type": m.Type}
+	for _, f := range fields[m.Type] {
+		var v interface{}
+		switch f {
+		case "actor":
+			v = m.Actor
+		case "target":
+			v = m.Target
+		case "pai":
+			v = m.Pai
+		case "consumed":
+			v = m.Consumed
+		case "tsumogiri":
+			v = m.Tsumogiri
+		case "id":
+			v = m.ID
+		case "names":
+			v = m.Names
+		case "bakaze":
+			v = m.Bakaze
+		case "kyoku":
+			v = m.Kyoku
+		case "honba":
+			v = m.Honba
+		case "kyotaku":
+			v = m.Kyotaku
+		case "oya":
+			v = m.Oya
+		case "dora_marker":
+			v = m.DoraMarker
+		case "tehais":
+			v = m.Tehais
+		case "deltas":
+			if m.Deltas == nil {
+				continue
+			}
+			v = m.Deltas
+		case "scores":
+			if m.Scores == nil {
+				continue
+			}
+			v = m.Scores
+		case "protocol":
+			v = m.Protocol
+		case "protocol_version":
+			v = m.ProtocolVersion
+		case "name":
+			v = m.Name
+		case "room":
+			v = m.Room
+		case "message":
+			v = m.Message
+		}
+		out[f] = v
+	}
+	return json.Marshal(out)
+}
+
+// Meld returns the meld declared by a chi, pon, daiminkan, ankan or kakan message. An
+// ankan is a concealed gang.
+func (m Message) Meld() (mj.Meld, error) {
+	tiles := make(mj.Hand, 0, 4)
+	for _, s := range append([]string{m.Pai}, m.Consumed...) {
+		if s == "" {
+			continue
+		}
+		t, _, err := ParseTile(s)
+		if err != nil {
+			return mj.Meld{}, err
+		}
+		tiles = append(tiles, t)
+	}
+	if len(tiles) == 0 {
+		return mj.Meld{}, fmt.Errorf("%w: %s without tiles", ErrProtocol, m.Type)
+	}
+
+	out := mj.Meld{Tile: tiles[0]}
+	switch m.Type {
+	case "chi":
+		out.Kind = mj.Chi
+		for _, t := range tiles {
+			if t.Less(out.Tile) {
+				out.Tile = t
+			}
+		}
+	case "pon":
+		out.Kind = mj.Peng
+	case "daiminkan", "kakan":
+		out.Kind = mj.Gang
+	case "ankan":
+		out.Kind, out.Concealed = mj.Gang, true
+	default:
+		return mj.Meld{}, fmt.Errorf("%w: %s is not a meld", ErrProtocol, m.Type)
+	}
+	for _, t := range tiles {
+		if !out.Contains(t) {
+			return mj.Meld{}, fmt.Errorf("%w: %s of %v", ErrProtocol, m.Type, append([]string{m.Pai}, m.Consumed...))
+		}
+	}
+	if !out.Valid() {
+		return mj.Meld{}, fmt.Errorf("%w: %s is not valid", ErrProtocol, out)
+	}
+	return out, nil
+}
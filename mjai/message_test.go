@@ -0,0 +1,101 @@
+package mjai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestParseTile(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		want string
+		red  bool
+	}{
+		{"1m", "w1", false},
+		{"9p", "c9", false},
+		{"5s", "b5", false},
+		{"5mr", "w5", true},
+		{"5pr", "c5", true},
+		{"E", "he", false},
+		{"N", "hn", false},
+		{"P", "hb", false},
+		{"F", "hf", false},
+		{"C", "hz", false},
+	} {
+		got, red, err := ParseTile(c.s)
+		if err != nil || mj.FormatTile(got) != c.want || red != c.red {
+			t.Errorf("ParseTile(%q) = %v, %v, %v, want %s, %v", c.s, got, red, err, c.want, c.red)
+		}
+		if s := FormatTile(got, red); s != c.s {
+			t.Errorf("FormatTile(%v, %v) = %q, want %q", got, red, s, c.s)
+		}
+	}
+	if got, _, err := ParseTile("?"); err != nil || got != (mj.Tile{}) {
+		t.Errorf("ParseTile(?) = %v, %v", got, err)
+	}
+	for _, s := range []string{"", "0m", "5z", "4mr", "Z", "10m"} {
+		if _, _, err := ParseTile(s); err == nil {
+			t.Errorf("ParseTile(%q) should fail", s)
+		}
+	}
+}
+
+func TestMessage_MarshalJSON(t *testing.T) {
+	for _, c := range []struct {
+		m    Message
+		want string
+	}{
+		{Message{Type: "none"}, `{"type":"none"}`},
+		{Message{Type: "dahai", Actor: 0, Pai: "5mr", Tsumogiri: true}, `{"actor":0,"pai":"5mr","tsumogiri":true,"type":"dahai"}`},
+		{Message{Type: "pon", Actor: 2, Target: 0, Pai: "C", Consumed: []string{"C", "C"}},
+			`{"actor":2,"consumed":["C","C"],"pai":"C","target":0,"type":"pon"}`},
+		{Message{Type: "join", Name: "mj", Room: "default", Actor: 3}, `{"name":"mj","room":"default","type":"join"}`},
+	} {
+		b, err := json.Marshal(c.m)
+		if err != nil || string(b) != c.want {
+			t.Errorf("Marshal(%+v) = %s, %v, want %s", c.m, b, err, c.want)
+		}
+		var back Message
+		if err := json.Unmarshal(b, &back); err != nil || back.Type != c.m.Type || back.Pai != c.m.Pai {
+			t.Errorf("Unmarshal(%s) = %+v, %v", b, back, err)
+		}
+	}
+}
+
+func TestMessage_Meld(t *testing.T) {
+	for _, c := range []struct {
+		m    Message
+		want string
+	}{
+		{Message{Type: "chi", Pai: "5mr", Consumed: []string{"6m", "4m"}}, "chi w4"},
+		{Message{Type: "pon", Pai: "E", Consumed: []string{"E", "E"}}, "peng he"},
+		{Message{Type: "daiminkan", Pai: "9s", Consumed: []string{"9s", "9s", "9s"}}, "gang b9"},
+		{Message{Type: "kakan", Pai: "5p", Consumed: []string{"5pr", "5p", "5p"}}, "gang c5"},
+		{Message{Type: "ankan", Consumed: []string{"C", "C", "C", "C"}}, "gang hz concealed"},
+	} {
+		got, err := c.m.Meld()
+		if err != nil {
+			t.Errorf("%+v: %v", c.m, err)
+			continue
+		}
+		s := got.Kind.String() + " " + mj.FormatTile(got.Tile)
+		if got.Concealed {
+			s += " concealed"
+		}
+		if s != c.want {
+			t.Errorf("Meld(%+v) = %s, want %s", c.m, s, c.want)
+		}
+	}
+	for _, m := range []Message{
+		{Type: "chi", Pai: "5m", Consumed: []string{"7m", "8m"}},
+		{Type: "pon", Pai: "E", Consumed: []string{"E", "S"}},
+		{Type: "dahai", Pai: "E"},
+		{Type: "pon"},
+	} {
+		if _, err := m.Meld(); err == nil {
+			t.Errorf("Meld(%+v) should fail", m)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package mjai
+
+import (
+	"fmt"
+
+	"github.com/nik0sc/mj"
+)
+
+// Unknown is written in place of a tile that the bot cannot see, such as another
+// player's draw.
+const Unknown = "?"
+
+var (
+	suits   = map[byte]mj.Suit{'m': mj.Wan, 'p': mj.Coin, 's': mj.Bamboo}
+	honours = map[string]mj.Value{
+		"E": mj.East, "S": mj.South, "W": mj.West, "N": mj.North,
+		"P": mj.Ban, "F": mj.Fa, "C": mj.Zhong,
+	}
+)
+
+// ParseTile turns an mjai tile into a Tile, and reports whether it is a red five. The
+// unknown tile "?" is the zero Tile.
+func ParseTile(s string) (t mj.Tile, red bool, err error) {
+	if s == Unknown {
+		return mj.Tile{}, false, nil
+	}
+	if v, ok := honours[s]; ok {
+		return mj.Tile{Suit: mj.Honour, Value: v}, false, nil
+	}
+	if len(s) == 3 && s[2] == 'r' && s[0] == '5' {
+		red, s = true, s[:2]
+	}
+	if len(s) == 2 && s[0] >= '1' && s[0] <= '9' {
+		if suit, ok := suits[s[1]]; ok {
+			return mj.Tile{Suit: suit, Value: mj.Value(s[0] - '0')}, red, nil
+		}
+	}
+	return mj.Tile{}, false, fmt.Errorf("bad mjai tile %q", s)
+}
+
+// FormatTile is the inverse of ParseTile. It returns "?" for the zero Tile, and panics
+// for tiles that are not used in mjai.
+func FormatTile(t mj.Tile, red bool) string {
+	switch {
+	case t == mj.Tile{}:
+		return Unknown
+	case t.Suit == mj.Honour:
+		for s, v := range honours {
+			if v == t.Value {
+				return s
+			}
+		}
+	case t.IsBasic():
+		for c, suit := range suits {
+			if suit == t.Suit {
+				s := string(rune('0'+t.Value)) + string(c)
+				if red && t.Value == 5 {
+					s += "r"
+				}
+				return s
+			}
+		}
+	}
+	panic(fmt.Sprintf("FormatTile: %v is not used in mjai", t))
+}
+
+// ParseTiles parses each tile with ParseTile. It returns the tiles, and the number of
+// them that are red fives.
+func ParseTiles(ss []string) (h mj.Hand, reds int, err error) {
+	h = make(mj.Hand, len(ss))
+	for i, s := range ss {
+		var red bool
+		if h[i], red, err = ParseTile(s); err != nil {
+			return nil, 0, err
+		}
+		if red {
+			reds++
+		}
+	}
+	return h, reds, nil
+}
@@ -11,6 +11,8 @@ var suitParse = map[uint8]Suit{
 	'w': Wan,
 	'h': Honour,
 	'f': Flower,
+	'a': Flower,
+	'j': Joker,
 }
 
 var honourParse = map[uint8]Value{
@@ -24,13 +26,16 @@ var honourParse = map[uint8]Value{
 }
 
 // ParseTile turns a 2-character string into a Tile.
-// The first character is the Suit and may be one of the characters "bcwhf" (for
-// Bamboo, Coin, Wan, Honour and Flower).
+// The first character is the Suit and may be one of the characters "bcwhfaj" (for
+// Bamboo, Coin, Wan, Honour, Flower, the animals, which are also in the Flower suit,
+// and Joker).
 // The second character is the Value and its permissible range depends on the Suit:
 //  - Bamboo, Coin and Wan: a digit between 1-9 inclusive.
 //  - Honour: one of the characters "eswnzfb" (for East, South, West, North,
 //      Zhong, Fa and Ban).
 //  - Flower: a digit between 1-8 inclusive.
+//  - Animal: a digit between 1-4 inclusive, for Rooster, Centipede, Cat and Mouse.
+//  - Joker: the digit 1.
 // Parsing errors are returned in err.
 func ParseTile(s string) (t Tile, err error) {
 	var ok bool
@@ -60,10 +65,22 @@ func ParseTile(s string) (t Tile, err error) {
 		}
 	case Flower:
 		t.Value = Value(s[1] - '0')
+		if s[0] == 'a' {
+			if t.Value < 1 || t.Value > 4 {
+				err = errors.New("invalid value for animal tile: " + string(s[1]))
+			}
+			t.Value += AnimalBase - 1
+			break
+		}
 		if t.Value < 1 || t.Value > 8 {
 			err = errors.New("invalid value for flower tile: " + string(s[1]))
 		}
-		t.Value |= FlowerBase
+		t.Value += FlowerBase - 1
+	case Joker:
+		t.Value = Value(s[1] - '0')
+		if t.Value != 1 {
+			err = errors.New("invalid value for joker: " + string(s[1]))
+		}
 	default:
 		panic("ParseTile: unreachable")
 	}
@@ -94,3 +111,40 @@ func MustParseHand(s string) Hand {
 	}
 	return h
 }
+
+// FormatTile is the inverse of ParseTile. It returns the 2-character representation of
+// a Tile, or "??" if the Tile is invalid.
+func FormatTile(t Tile) string {
+	if !t.Valid() {
+		return "??"
+	}
+	switch t.Suit {
+	case Honour:
+		for c, v := range honourParse {
+			if v == t.Value {
+				return "h" + string(c)
+			}
+		}
+	case Flower:
+		if t.IsAnimal() {
+			return "a" + string(rune('1'+t.Value-AnimalBase))
+		}
+		return "f" + string(rune('1'+t.Value-FlowerBase))
+	}
+	for c, s := range suitParse {
+		if s == t.Suit && c != 'a' {
+			return string(c) + string(rune('0'+t.Value))
+		}
+	}
+	panic("FormatTile: unreachable")
+}
+
+// FormatHand is the inverse of ParseHand. It returns the tiles formatted by FormatTile,
+// separated by spaces.
+func FormatHand(h Hand) string {
+	ss := make([]string, len(h))
+	for i, t := range h {
+		ss[i] = FormatTile(t)
+	}
+	return strings.Join(ss, " ")
+}
@@ -0,0 +1,3 @@
+// Package prob estimates the chance of a hand improving or winning before the wall runs
+// out, either by simulation or exactly.
+package prob
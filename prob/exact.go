@@ -0,0 +1,56 @@
+package prob
+
+import (
+	"github.com/nik0sc/mj"
+)
+
+// Curve returns the exact chance of drawing at least one of the tiles within each number
+// of draws from 1 to draws, such as the waits of a hand from wait.Find or the tiles that
+// it accepts from ukeire.Accept. Element i is the chance within i+1 draws.
+//
+// Each draw is taken without replacement from the unseen tiles. Bonus tiles and jokers
+// in the unseen Counter are ignored, since they are replaced when drawn. Repeated tiles
+// are only counted once. If there are fewer unseen tiles than draws, the curve stops
+// when they run out.
+func Curve(tiles []mj.Tile, unseen mj.Counter, draws int) []float64 {
+	pool, good := 0, 0
+	unseen.ForEach(func(t mj.Tile, n int) bool {
+		if t.CanMeld() {
+			pool += n
+		}
+		return true
+	})
+	seen := make(map[mj.Tile]bool, len(tiles))
+	for _, t := range tiles {
+		if !seen[t] && t.CanMeld() {
+			seen[t] = true
+			good += unseen.Get(t)
+		}
+	}
+
+	if draws > pool {
+		draws = pool
+	}
+	out := make([]float64, 0, draws)
+	// miss is the chance that none of the first i draws was one of the tiles, which is
+	// the hypergeometric C(pool-good, i) / C(pool, i)
+	miss := 1.0
+	for i := 0; i < draws; i++ {
+		miss *= float64(pool-good-i) / float64(pool-i)
+		if miss < 0 {
+			miss = 0
+		}
+		out = append(out, 1-miss)
+	}
+	return out
+}
+
+// Draw returns the exact chance of drawing at least one of the tiles within the given
+// number of draws. See Curve for how the tiles and unseen Counter are used.
+func Draw(tiles []mj.Tile, unseen mj.Counter, draws int) float64 {
+	c := Curve(tiles, unseen, draws)
+	if len(c) == 0 {
+		return 0
+	}
+	return c[len(c)-1]
+}
@@ -0,0 +1,51 @@
+package prob
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestCurve(t *testing.T) {
+	unseen := mj.MustParseHand("b1 b1 b2 b3 f1").ToCount()
+	waits := mj.MustParseHand("b1 b1")
+
+	got := Curve(waits, unseen, 10)
+	// 2 of 4 melding tiles: miss 2/4, then 2/4*1/3, then 0
+	want := []float64{1 - 2.0/4, 1 - 2.0/4*1/3, 1, 1}
+	if len(got) != len(want) {
+		t.Fatalf("Curve() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("Curve()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if got := Draw(nil, unseen, 3); got != 0 {
+		t.Errorf("Draw() with no tiles = %v, want 0", got)
+	}
+	if got := Draw(waits, unseen, 0); got != 0 {
+		t.Errorf("Draw() with no draws = %v, want 0", got)
+	}
+}
+
+func TestDraw(t *testing.T) {
+	hand := mj.MustParseHand("b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b4 b5")
+	unseen := unseenFor(hand, mj.HongKong)
+	got := Draw(mj.MustParseHand("b3 b6"), unseen, 10)
+
+	// 7 winning tiles among 123 unseen melding tiles
+	want := 1 - math.Exp(lchoose(116, 10)-lchoose(123, 10))
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("Draw() = %v, want %v", got, want)
+	}
+}
+
+func lchoose(n, k float64) float64 {
+	a, _ := math.Lgamma(n + 1)
+	b, _ := math.Lgamma(k + 1)
+	c, _ := math.Lgamma(n - k + 1)
+	return a - b - c
+}
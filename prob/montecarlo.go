@@ -0,0 +1,198 @@
+package prob
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+	"github.com/nik0sc/mj/special"
+	"github.com/nik0sc/mj/wait"
+)
+
+const (
+	defaultTrials = 1000
+	// z is the standard score for a 95% confidence interval.
+	z = 1.959964
+)
+
+// Simulation estimates the chance of a hand reaching tenpai and winning by playing out
+// random draws from the unseen tiles. Other players are not simulated: every draw goes
+// to the hand, and no discards are claimed.
+// The zero value is ready to use, and runs 1000 trials with ShantenPolicy.
+type Simulation struct {
+	// Rules gives the hand size, tileset and special hands.
+	Rules mj.Ruleset
+	// Policy chooses the discard after each draw. If nil, ShantenPolicy is used.
+	Policy Policy
+	// Trials is the number of games to play out. If 0, 1000 trials are run.
+	Trials int
+	// Workers is the number of goroutines to run trials on. If 0, runtime.GOMAXPROCS
+	// goroutines are used.
+	Workers int
+	// Seed seeds the random draws. Each trial is seeded separately, so the result does
+	// not depend on Workers.
+	Seed int64
+}
+
+// Estimate is an estimated probability with its 95% confidence interval.
+type Estimate struct {
+	P         float64
+	Low, High float64
+}
+
+// String returns the estimate as a percentage with its interval.
+func (e Estimate) String() string {
+	return fmt.Sprintf("%.1f%% (%.1f%%-%.1f%%)", 100*e.P, 100*e.Low, 100*e.High)
+}
+
+// Result is the outcome of a Simulation.
+type Result struct {
+	Trials int
+	// Tenpai is the chance of the hand waiting to win at some point, including at the
+	// start.
+	Tenpai Estimate
+	// Win is the chance of drawing a winning tile.
+	Win Estimate
+}
+
+// ErrBadHand is returned when a hand cannot be simulated.
+var ErrBadHand = errors.New("hand cannot be simulated")
+
+// Run simulates the hand, which holds 3n+1 concealed tiles, over the given number of
+// draws. The unseen Counter holds the tiles that may be drawn. Bonus tiles and jokers
+// in it are ignored, since they are replaced when drawn. If there are fewer unseen tiles
+// than draws, every trial draws all of them.
+func (s Simulation) Run(hand mj.Hand, unseen mj.Counter, draws int) (Result, error) {
+	if len(hand)%3 != 1 || !hand.Valid() {
+		return Result{}, fmt.Errorf("%w: %d tiles", ErrBadHand, len(hand))
+	}
+	if draws < 0 {
+		return Result{}, fmt.Errorf("%w: %d draws", ErrBadHand, draws)
+	}
+
+	var pool mj.Hand
+	for _, t := range unseen.ToHand(true) {
+		if t.CanMeld() {
+			pool = append(pool, t)
+		}
+	}
+	if draws > len(pool) {
+		draws = len(pool)
+	}
+
+	trials := s.Trials
+	if trials == 0 {
+		trials = defaultTrials
+	}
+	workers := s.Workers
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	policy := s.Policy
+	if policy == nil {
+		policy = ShantenPolicy{}
+	}
+
+	var (
+		mu          sync.Mutex
+		tenpai, win int
+		wg          sync.WaitGroup
+		next        = make(chan int)
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				rng := rand.New(rand.NewSource(s.Seed + int64(i)))
+				reached, won := trial(hand, pool, draws, rng, policy, s.Rules)
+				mu.Lock()
+				if reached {
+					tenpai++
+				}
+				if won {
+					win++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < trials; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	return Result{
+		Trials: trials,
+		Tenpai: wilson(tenpai, trials),
+		Win:    wilson(win, trials),
+	}, nil
+}
+
+// trial plays out one game and returns whether the hand reached tenpai and won.
+func trial(start mj.Hand, pool mj.Hand, draws int, rng *rand.Rand, policy Policy, rs mj.Ruleset) (tenpai, win bool) {
+	hand := make(mj.Hand, len(start))
+	copy(hand, start)
+	wall := make(mj.Hand, len(pool))
+	copy(wall, pool)
+	rng.Shuffle(len(wall), wall.Swap)
+
+	unseen := pool.ToCount()
+	waits := waitsOf(hand, rs)
+	tenpai = len(waits) > 0
+	for _, t := range wall[:draws] {
+		if waits[t] {
+			return true, true
+		}
+		unseen = unseen.Remove(t)
+		hand = hand.Append(t)
+		d := policy.Discard(hand, unseen, rs)
+		for i := range hand {
+			if hand[i] == d {
+				hand = hand.Remove(i)
+				break
+			}
+		}
+		waits = waitsOf(hand, rs)
+		tenpai = tenpai || len(waits) > 0
+	}
+	return tenpai, false
+}
+
+// waitsOf returns the tiles that complete the hand, in the usual form or as a special
+// hand. The shanten is checked first, so that the wait analysis only runs on hands that
+// are waiting.
+func waitsOf(hand mj.Hand, rs mj.Ruleset) map[mj.Tile]bool {
+	if handcheck.Shanten(hand, rs) > 0 {
+		return nil
+	}
+	out := make(map[mj.Tile]bool)
+	for _, w := range wait.Classify(hand, rs) {
+		out[w.Tile] = true
+	}
+	if len(hand) == rs.Size() {
+		for _, t := range special.Waits(hand, rs) {
+			out[t] = true
+		}
+	}
+	return out
+}
+
+// wilson returns the Wilson score interval for k successes in n trials.
+func wilson(k, n int) Estimate {
+	if n == 0 {
+		return Estimate{}
+	}
+	p := float64(k) / float64(n)
+	nf := float64(n)
+	denom := 1 + z*z/nf
+	centre := (p + z*z/(2*nf)) / denom
+	half := z * math.Sqrt(p*(1-p)/nf+z*z/(4*nf*nf)) / denom
+	return Estimate{P: p, Low: math.Max(0, centre-half), High: math.Min(1, centre+half)}
+}
@@ -0,0 +1,78 @@
+package prob
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+// unseenFor returns every tile in the tileset apart from the hand.
+func unseenFor(hand mj.Hand, rs mj.Ruleset) mj.Counter {
+	m := rs.NewCounterAtStart().Map()
+	for _, t := range hand {
+		m[t]--
+	}
+	c, err := mj.NewCounter(m)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func TestSimulation_Run(t *testing.T) {
+	hand := mj.MustParseHand("b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b4 b5")
+	unseen := unseenFor(hand, mj.HongKong)
+
+	sim := Simulation{Trials: 200, Seed: 1}
+	r, err := sim.Run(hand, unseen, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Tenpai.P != 1 {
+		t.Errorf("tenpai = %v, want 1 for a waiting hand", r.Tenpai)
+	}
+	// 7 winning tiles among 123 unseen, over 10 draws: about 45%
+	if r.Win.P < 0.3 || r.Win.P > 0.7 {
+		t.Errorf("win = %v, want about 50%%", r.Win)
+	}
+	if r.Win.Low > r.Win.P || r.Win.High < r.Win.P {
+		t.Errorf("interval %v does not contain the estimate", r.Win)
+	}
+
+	sim.Workers = 1
+	r2, err := sim.Run(hand, unseen, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r2 != r {
+		t.Errorf("result depends on workers: %+v and %+v", r, r2)
+	}
+
+	r, err = sim.Run(hand, unseen, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Win.P != 0 {
+		t.Errorf("win with no draws = %v", r.Win)
+	}
+}
+
+func TestSimulation_RunFar(t *testing.T) {
+	hand := mj.MustParseHand("b1 b4 b7 c2 c5 c8 w3 w6 w9 he hs hw hn")
+	sim := Simulation{Trials: 50, Seed: 2, Rules: mj.Ruleset{Specials: mj.NoSpecialHands}}
+	r, err := sim.Run(hand, unseenFor(hand, mj.HongKong), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Tenpai.P != 0 || r.Win.P != 0 {
+		t.Errorf("a hand 8 tiles away reached %v tenpai and %v win in 3 draws", r.Tenpai, r.Win)
+	}
+}
+
+func TestSimulation_RunErrors(t *testing.T) {
+	var sim Simulation
+	if _, err := sim.Run(mj.MustParseHand("b1 b2"), mj.Counter{}, 1); !errors.Is(err, ErrBadHand) {
+		t.Errorf("err = %v, want ErrBadHand", err)
+	}
+}
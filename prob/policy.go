@@ -0,0 +1,82 @@
+package prob
+
+import (
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+	"github.com/nik0sc/mj/ukeire"
+)
+
+// Policy chooses a tile to discard from a hand with 3n+2 concealed tiles. The unseen
+// Counter holds the tiles that the player has not seen. A Policy is called from several
+// goroutines at once, so it must be safe for concurrent use.
+type Policy interface {
+	Discard(hand mj.Hand, unseen mj.Counter, rs mj.Ruleset) mj.Tile
+}
+
+// PolicyFunc adapts a function to the Policy interface.
+type PolicyFunc func(hand mj.Hand, unseen mj.Counter, rs mj.Ruleset) mj.Tile
+
+// Discard calls f.
+func (f PolicyFunc) Discard(hand mj.Hand, unseen mj.Counter, rs mj.Ruleset) mj.Tile {
+	return f(hand, unseen, rs)
+}
+
+// ShantenPolicy discards a tile that leaves the lowest shanten. Among those, it discards
+// the tile with the fewest nearby tiles in the hand, then the lowest tile. It is fast
+// enough to be the default policy for simulations.
+type ShantenPolicy struct{}
+
+// Discard implements Policy.
+func (ShantenPolicy) Discard(hand mj.Hand, unseen mj.Counter, rs mj.Ruleset) mj.Tile {
+	var best mj.Tile
+	bestShanten, bestNear := 0, 0
+	for i, t := range hand {
+		if best.Valid() && t == best {
+			continue
+		}
+		s := handcheck.Shanten(hand.Remove(i), rs)
+		near := nearby(hand, t)
+		if !best.Valid() || s < bestShanten ||
+			s == bestShanten && (near < bestNear || near == bestNear && t.Less(best)) {
+			best, bestShanten, bestNear = t, s, near
+		}
+	}
+	return best
+}
+
+// AcceptancePolicy discards the tile recommended by ukeire.Discards, which also weighs
+// the unseen copies of the tiles that the hand accepts. It plays better than
+// ShantenPolicy, but is much slower.
+type AcceptancePolicy struct{}
+
+// Discard implements Policy.
+func (AcceptancePolicy) Discard(hand mj.Hand, unseen mj.Counter, rs mj.Ruleset) mj.Tile {
+	// ukeire counts the copies that are not in the hand or visible, so turn the unseen
+	// tiles into the visible ones
+	m := make(map[mj.Tile]int)
+	held := hand.ToCount()
+	for _, t := range rs.Tiles.Unique() {
+		if n := rs.Tiles.Count(t) - held.Get(t) - unseen.Get(t); n > 0 {
+			m[t] = n
+		}
+	}
+	visible, err := mj.NewCounter(m)
+	if err != nil {
+		panic(err)
+	}
+	return ukeire.Discards(hand, visible, rs)[0].Tile
+}
+
+// nearby counts the other tiles in the hand that could form a set with the tile.
+func nearby(hand mj.Hand, t mj.Tile) int {
+	n := -1
+	for _, t2 := range hand {
+		switch {
+		case t2 == t:
+			n++
+		case t.IsBasic() && t2.Suit == t.Suit && t2.Value+2 >= t.Value && t2.Value <= t.Value+2:
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,18 @@
+package prob
+
+import (
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestPolicies(t *testing.T) {
+	hand := mj.MustParseHand("b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b4 b5 hb")
+	want := mj.Tile{Suit: mj.Honour, Value: mj.Ban}
+	unseen := unseenFor(hand, mj.HongKong)
+	for _, p := range []Policy{ShantenPolicy{}, AcceptancePolicy{}} {
+		if got := p.Discard(hand, unseen, mj.HongKong); got != want {
+			t.Errorf("%T discarded %s, want %s", p, got, want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package record reads and writes game logs, and replays them to rebuild the table at
+// any step.
+//
+// A log is a sequence of JSON objects, one per line. The first is a "start" entry, and
+// each one after that is an event in the order it happened:
+//
+//   {"type":"start","seed":1,"rules":{...},"hands":["b1 b2 ...", ...],"bonus":["f1", ""]}
+//   {"type":"draw","seat":0,"tile":"c5"}
+//   {"type":"discard","seat":0,"tile":"hn"}
+//   {"type":"meld","seat":2,"from":0,"tile":"hn","meld":"peng hn"}
+//   {"type":"bonus","seat":1,"tile":"f3"}
+//   {"type":"win","seat":2,"from":1,"tile":"b4","points":3}
+//   {"type":"exhausted"}
+//
+// Tiles and hands are written as for mj.FormatTile and mj.FormatHand. The start entry
+// holds the seed of the wall, the full mj.Ruleset, and each seat's dealt hand and bonus
+// tiles after replacement. A meld is written as its kind and its first tile; "tile" is
+// the discard it was claimed from, and is left out for a concealed gang. "from" is the
+// seat that discarded the claimed or winning tile, or the winner for a self-drawn win.
+package record
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nik0sc/mj"
+)
+
+// The types of Entry.
+const (
+	TypeStart     = "start"
+	TypeDraw      = "draw"
+	TypeDiscard   = "discard"
+	TypeMeld      = "meld"
+	TypeBonus     = "bonus"
+	TypeWin       = "win"
+	TypeExhausted = "exhausted"
+)
+
+// ErrInconsistent is returned when a log does not describe a game that could happen.
+var ErrInconsistent = errors.New("inconsistent log")
+
+// Entry is one line of a log. Only the fields used by its Type are set.
+type Entry struct {
+	Type string `json:"type"`
+	Seat int    `json:"seat,omitempty"`
+	From int    `json:"from,omitempty"`
+	Tile string `json:"tile,omitempty"`
+	Meld string `json:"meld,omitempty"`
+	// Points is the score of a win.
+	Points int `json:"points,omitempty"`
+
+	Seed  int64       `json:"seed,omitempty"`
+	Rules *mj.Ruleset `json:"rules,omitempty"`
+	Hands []string    `json:"hands,omitempty"`
+	Bonus []string    `json:"bonus,omitempty"`
+}
+
+// Start returns the start entry for a game.
+func Start(seed int64, rs mj.Ruleset, hands, bonus []mj.Hand) Entry {
+	e := Entry{Type: TypeStart, Seed: seed, Rules: &rs}
+	for _, h := range hands {
+		e.Hands = append(e.Hands, mj.FormatHand(h))
+	}
+	for _, b := range bonus {
+		e.Bonus = append(e.Bonus, mj.FormatHand(b))
+	}
+	return e
+}
+
+// FormatMeld returns the meld's kind and first tile, like "chi b1". A concealed gang
+// is written as "cgang".
+func FormatMeld(m mj.Meld) string {
+	kind := m.Kind.String()
+	if m.Kind == mj.Gang && m.Concealed {
+		kind = "cgang"
+	}
+	return kind + " " + mj.FormatTile(m.Tile)
+}
+
+// ParseMeld is the inverse of FormatMeld. Melds other than concealed gangs are not
+// concealed.
+func ParseMeld(s string) (mj.Meld, error) {
+	fs := strings.Fields(s)
+	if len(fs) != 2 {
+		return mj.Meld{}, fmt.Errorf("meld %q: want a kind and a tile", s)
+	}
+	t, err := mj.ParseTile(fs[1])
+	if err != nil {
+		return mj.Meld{}, fmt.Errorf("meld %q: %w", s, err)
+	}
+	m := mj.Meld{Tile: t}
+	switch fs[0] {
+	case mj.Chi.String():
+		m.Kind = mj.Chi
+	case mj.Peng.String():
+		m.Kind = mj.Peng
+	case mj.Gang.String():
+		m.Kind = mj.Gang
+	case "cgang":
+		m.Kind, m.Concealed = mj.Gang, true
+	default:
+		return mj.Meld{}, fmt.Errorf("meld %q: unknown kind", s)
+	}
+	if !m.Valid() {
+		return mj.Meld{}, fmt.Errorf("meld %q is not valid", s)
+	}
+	return m, nil
+}
+
+// Writer writes a log.
+type Writer struct {
+	enc *json.Encoder
+}
+
+// NewWriter returns a Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{enc: json.NewEncoder(w)}
+}
+
+// Write writes an entry on its own line.
+func (w *Writer) Write(e Entry) error {
+	return w.enc.Encode(e)
+}
+
+// Read reads every entry of a log. Blank lines are skipped.
+func Read(r io.Reader) ([]Entry, error) {
+	var out []Entry
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	line := 0
+	for sc.Scan() {
+		line++
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
@@ -0,0 +1,62 @@
+package record
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestParseMeld(t *testing.T) {
+	for _, m := range []mj.Meld{
+		{Kind: mj.Chi, Tile: mj.Tile{Suit: mj.Bamboo, Value: 1}},
+		{Kind: mj.Peng, Tile: mj.Tile{Suit: mj.Honour, Value: mj.Zhong}},
+		{Kind: mj.Gang, Tile: mj.Tile{Suit: mj.Coin, Value: 9}},
+		{Kind: mj.Gang, Tile: mj.Tile{Suit: mj.Wan, Value: 5}, Concealed: true},
+	} {
+		got, err := ParseMeld(FormatMeld(m))
+		if err != nil || got != m {
+			t.Errorf("ParseMeld(%q) = %v, %v, want %v", FormatMeld(m), got, err, m)
+		}
+	}
+	for _, s := range []string{"", "chi", "chi b8", "pong b1", "peng f1", "peng b1 b1"} {
+		if _, err := ParseMeld(s); err == nil {
+			t.Errorf("ParseMeld(%q) should fail", s)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	hands := []mj.Hand{mj.MustParseHand("b1 b2 b3"), mj.MustParseHand("c1 c1 c1")}
+	bonus := []mj.Hand{mj.MustParseHand("f1"), nil}
+	entries := []Entry{
+		Start(7, mj.HongKong, hands, bonus),
+		{Type: TypeDraw, Seat: 1, Tile: "c1"},
+		{Type: TypeMeld, Seat: 0, From: 1, Tile: "c1", Meld: "peng c1"},
+		{Type: TypeWin, Seat: 0, From: 0, Tile: "b3", Points: 8},
+		{Type: TypeExhausted},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, e := range entries {
+		if err := w.Write(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := bytes.Count(buf.Bytes(), []byte("\n")); n != len(entries) {
+		t.Errorf("wrote %d lines, want %d", n, len(entries))
+	}
+
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("Read() = %+v, want %+v", got, entries)
+	}
+	if got[0].Hands[1] != "c1 c1 c1" || got[0].Bonus[0] != "f1" || got[2].Meld != "peng c1" || got[3].Points != 8 {
+		t.Errorf("unexpected entries %+v", got)
+	}
+}
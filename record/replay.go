@@ -0,0 +1,401 @@
+package record
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+)
+
+// Table is the state of a game after some of its log has been replayed.
+type Table struct {
+	Rules mj.Ruleset
+	Seed  int64
+	// Step is the number of entries replayed after the start entry.
+	Step int
+	// Hands holds each seat's concealed tiles, sorted.
+	Hands []mj.Hand
+	// Melds holds each seat's declared melds.
+	Melds [][]mj.Meld
+	// Bonus holds each seat's bonus tiles.
+	Bonus []mj.Hand
+	// Discards holds each seat's discards in order, including discards that were
+	// claimed by other seats.
+	Discards []mj.Hand
+	// Wall holds the tiles left in the wall, with the next tile to draw first. It is
+	// rebuilt from the seed, and is nil if the hands in the start entry are not the ones
+	// that mj.Ruleset.Deal deals for the seed, in which case draws are not checked
+	// against it.
+	Wall mj.Hand
+	// Live is the number of tiles left in the wall.
+	Live int
+	// Turn is the seat to draw or discard next.
+	Turn int
+	// Winner is the seat that won, or -1.
+	Winner int
+	// Over is true once the game has been won or the wall has run out.
+	Over bool
+}
+
+// clone returns a deep copy of the Table.
+func (t Table) clone() Table {
+	hands := func(hs []mj.Hand) []mj.Hand {
+		out := make([]mj.Hand, len(hs))
+		for i, h := range hs {
+			out[i] = append(mj.Hand(nil), h...)
+		}
+		return out
+	}
+	t.Hands, t.Bonus, t.Discards = hands(t.Hands), hands(t.Bonus), hands(t.Discards)
+	melds := make([][]mj.Meld, len(t.Melds))
+	for i, ms := range t.Melds {
+		melds[i] = append([]mj.Meld(nil), ms...)
+	}
+	t.Melds = melds
+	if t.Wall != nil {
+		t.Wall = append(mj.Hand{}, t.Wall...)
+	}
+	return t
+}
+
+// Replay steps through a log, checking that each entry could have happened.
+//
+// The following are checked:
+//  - The dealt hands have the right number of tiles, and no tile is used more times
+//    than there are copies of it.
+//  - Seats draw and discard in turn, and only draw when they are due a tile.
+//  - A discarded tile, and the tiles of a meld other than the claimed tile, are in the
+//    seat's hand.
+//  - A claim is made on the latest discard, by another seat, before anyone draws. Only
+//    the next seat may claim a chi, and not if the Ruleset forbids chis.
+//  - Drawn tiles are the tiles that the wall holds, when the wall can be rebuilt. A tile
+//    is drawn from the back of the wall after a gang or a bonus tile.
+//  - A winning hand is complete, and the wall is empty when the game is exhausted.
+type Replay struct {
+	entries []Entry
+	next    int
+	t       Table
+
+	// lastFrom is the seat that made the latest discard, or -1 if it can no longer be
+	// claimed.
+	lastFrom int
+	last     mj.Tile
+	fromBack bool
+}
+
+// NewReplay starts a replay of the log. The first entry must be a start entry.
+func NewReplay(entries []Entry) (*Replay, error) {
+	if len(entries) == 0 || entries[0].Type != TypeStart {
+		return nil, fmt.Errorf("%w: log does not begin with a start entry", ErrInconsistent)
+	}
+	r := &Replay{entries: entries, next: 1, lastFrom: -1}
+	if err := r.start(entries[0]); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Table returns a copy of the table after the entries replayed so far.
+func (r *Replay) Table() Table {
+	return r.t.clone()
+}
+
+// Next replays the next entry and returns it. It returns io.EOF if every entry has been
+// replayed, and an error wrapping ErrInconsistent if the entry could not have happened.
+func (r *Replay) Next() (Entry, error) {
+	if r.next >= len(r.entries) {
+		return Entry{}, io.EOF
+	}
+	e := r.entries[r.next]
+	if err := r.apply(e); err != nil {
+		return e, fmt.Errorf("entry %d (%s): %w", r.next, e.Type, err)
+	}
+	r.next++
+	r.t.Step++
+	return e, nil
+}
+
+// At returns the table after the start entry and the given number of entries after it.
+func At(entries []Entry, step int) (Table, error) {
+	r, err := NewReplay(entries)
+	if err != nil {
+		return Table{}, err
+	}
+	for r.t.Step < step {
+		if _, err := r.Next(); err != nil {
+			if err == io.EOF {
+				return Table{}, fmt.Errorf("step %d is past the end of the log", step)
+			}
+			return Table{}, err
+		}
+	}
+	return r.Table(), nil
+}
+
+// Verify replays the whole log, and returns the final table.
+func Verify(entries []Entry) (Table, error) {
+	r, err := NewReplay(entries)
+	if err != nil {
+		return Table{}, err
+	}
+	for {
+		if _, err := r.Next(); err == io.EOF {
+			return r.Table(), nil
+		} else if err != nil {
+			return Table{}, err
+		}
+	}
+}
+
+func (r *Replay) start(e Entry) error {
+	t := &r.t
+	if e.Rules != nil {
+		t.Rules = *e.Rules
+	}
+	t.Seed, t.Winner = e.Seed, -1
+	n := t.Rules.Players()
+	if len(e.Hands) != n {
+		return fmt.Errorf("%w: %d hands for %d players", ErrInconsistent, len(e.Hands), n)
+	}
+	if len(e.Bonus) > n {
+		return fmt.Errorf("%w: %d bonus hands for %d players", ErrInconsistent, len(e.Bonus), n)
+	}
+
+	t.Hands = make([]mj.Hand, n)
+	t.Melds = make([][]mj.Meld, n)
+	t.Bonus = make([]mj.Hand, n)
+	t.Discards = make([]mj.Hand, n)
+	used := make(map[mj.Tile]int)
+	for p := 0; p < n; p++ {
+		h, err := parseHand(e.Hands[p])
+		if err != nil {
+			return fmt.Errorf("hand %d: %w", p, err)
+		}
+		if len(h) != t.Rules.Size() {
+			return fmt.Errorf("%w: hand %d has %d tiles, want %d", ErrInconsistent, p, len(h), t.Rules.Size())
+		}
+		sort.Sort(h)
+		t.Hands[p] = h
+		if p < len(e.Bonus) {
+			if t.Bonus[p], err = parseHand(e.Bonus[p]); err != nil {
+				return fmt.Errorf("bonus %d: %w", p, err)
+			}
+		}
+		for _, tile := range append(append(mj.Hand(nil), h...), t.Bonus[p]...) {
+			used[tile]++
+			if used[tile] > t.Rules.Tiles.Count(tile) {
+				return fmt.Errorf("%w: more than %d of %s dealt", ErrInconsistent,
+					t.Rules.Tiles.Count(tile), mj.FormatTile(tile))
+			}
+		}
+		t.Live -= len(h) + len(t.Bonus[p])
+	}
+	t.Live += len(t.Rules.Tiles.AllTiles())
+
+	// rebuild the wall if the hands are the ones dealt for the seed
+	hands, bonus, wall := t.Rules.Deal(rand.New(rand.NewSource(t.Seed)))
+	for p := 0; p < n; p++ {
+		if mj.FormatHand(hands[p]) != mj.FormatHand(t.Hands[p]) ||
+			mj.FormatHand(bonus[p]) != mj.FormatHand(t.Bonus[p]) {
+			return nil
+		}
+	}
+	t.Wall = wall
+	return nil
+}
+
+func (r *Replay) apply(e Entry) error {
+	t := &r.t
+	if t.Over {
+		return fmt.Errorf("%w: the game is over", ErrInconsistent)
+	}
+	if e.Type != TypeExhausted && (e.Seat < 0 || e.Seat >= len(t.Hands)) {
+		return fmt.Errorf("%w: no seat %d", ErrInconsistent, e.Seat)
+	}
+
+	var tile mj.Tile
+	if e.Tile != "" {
+		var err error
+		if tile, err = mj.ParseTile(e.Tile); err != nil {
+			return err
+		}
+	}
+
+	switch e.Type {
+	case TypeDraw, TypeBonus:
+		return r.draw(e.Seat, tile, e.Type == TypeBonus)
+	case TypeDiscard:
+		if err := r.turn(e.Seat, 2); err != nil {
+			return err
+		}
+		if !r.take(e.Seat, tile) {
+			return fmt.Errorf("%w: seat %d discarded %s, which it does not have", ErrInconsistent, e.Seat, e.Tile)
+		}
+		t.Discards[e.Seat] = append(t.Discards[e.Seat], tile)
+		r.last, r.lastFrom = tile, e.Seat
+		t.Turn = (e.Seat + 1) % len(t.Hands)
+		return nil
+	case TypeMeld:
+		m, err := ParseMeld(e.Meld)
+		if err != nil {
+			return err
+		}
+		if m.Concealed {
+			return r.concealedGang(e.Seat, m)
+		}
+		return r.claim(e.Seat, e.From, tile, m)
+	case TypeWin:
+		return r.win(e.Seat, e.From, tile)
+	case TypeExhausted:
+		if t.Live != 0 {
+			return fmt.Errorf("%w: exhausted with %d tiles in the wall", ErrInconsistent, t.Live)
+		}
+		t.Over = true
+		return nil
+	}
+	return fmt.Errorf("%w: unknown entry type %q", ErrInconsistent, e.Type)
+}
+
+// turn checks that it is the seat's turn, and that its hand has 3n+rem tiles.
+func (r *Replay) turn(seat, rem int) error {
+	if seat != r.t.Turn {
+		return fmt.Errorf("%w: seat %d moved on seat %d's turn", ErrInconsistent, seat, r.t.Turn)
+	}
+	if len(r.t.Hands[seat])%3 != rem {
+		return fmt.Errorf("%w: seat %d has %d tiles", ErrInconsistent, seat, len(r.t.Hands[seat]))
+	}
+	return nil
+}
+
+func (r *Replay) draw(seat int, tile mj.Tile, bonus bool) error {
+	t := &r.t
+	if err := r.turn(seat, 1); err != nil {
+		return err
+	}
+	if bonus != tile.IsBonus() {
+		return fmt.Errorf("%w: %s drawn as the wrong kind of tile", ErrInconsistent, mj.FormatTile(tile))
+	}
+	if t.Live == 0 {
+		return fmt.Errorf("%w: the wall is empty", ErrInconsistent)
+	}
+	if t.Wall != nil {
+		want := t.Wall[0]
+		if r.fromBack {
+			want = t.Wall[len(t.Wall)-1]
+		}
+		if tile != want {
+			return fmt.Errorf("%w: seat %d drew %s, but the wall holds %s", ErrInconsistent,
+				seat, mj.FormatTile(tile), mj.FormatTile(want))
+		}
+		if r.fromBack {
+			t.Wall = t.Wall[:len(t.Wall)-1]
+		} else {
+			t.Wall = t.Wall[1:]
+		}
+	}
+	t.Live--
+	r.lastFrom = -1
+
+	if bonus {
+		t.Bonus[seat] = append(t.Bonus[seat], tile)
+		r.fromBack = true
+		return nil
+	}
+	t.Hands[seat] = t.Hands[seat].Append(tile)
+	sort.Sort(t.Hands[seat])
+	r.fromBack = false
+	return nil
+}
+
+func (r *Replay) claim(seat, from int, tile mj.Tile, m mj.Meld) error {
+	t := &r.t
+	n := len(t.Hands)
+	switch {
+	case r.lastFrom < 0 || from != r.lastFrom || tile != r.last:
+		return fmt.Errorf("%w: seat %d claimed %s from seat %d, which is not the latest discard",
+			ErrInconsistent, seat, mj.FormatTile(tile), from)
+	case seat == from:
+		return fmt.Errorf("%w: seat %d claimed its own discard", ErrInconsistent, seat)
+	case !m.Contains(tile):
+		return fmt.Errorf("%w: %s does not contain %s", ErrInconsistent, m, mj.FormatTile(tile))
+	case m.Kind == mj.Chi && (t.Rules.NoChi || seat != (from+1)%n):
+		return fmt.Errorf("%w: seat %d cannot chi from seat %d", ErrInconsistent, seat, from)
+	}
+
+	claimed := tile
+	for _, mt := range m.Tiles() {
+		if mt == claimed {
+			claimed = mj.Tile{}
+		} else if !r.take(seat, mt) {
+			return fmt.Errorf("%w: seat %d melded %s without %s", ErrInconsistent, seat, m, mj.FormatTile(mt))
+		}
+	}
+	t.Melds[seat] = append(t.Melds[seat], m)
+	t.Turn, r.lastFrom = seat, -1
+	r.fromBack = m.Kind == mj.Gang
+	return nil
+}
+
+func (r *Replay) concealedGang(seat int, m mj.Meld) error {
+	if err := r.turn(seat, 2); err != nil {
+		return err
+	}
+	for _, mt := range m.Tiles() {
+		if !r.take(seat, mt) {
+			return fmt.Errorf("%w: seat %d melded %s without %s", ErrInconsistent, seat, m, mj.FormatTile(mt))
+		}
+	}
+	r.t.Melds[seat] = append(r.t.Melds[seat], m)
+	r.lastFrom, r.fromBack = -1, true
+	return nil
+}
+
+func (r *Replay) win(seat, from int, tile mj.Tile) error {
+	t := &r.t
+	hand := t.Hands[seat]
+	if from == seat {
+		if err := r.turn(seat, 2); err != nil {
+			return err
+		}
+		if hand.ToCount().Get(tile) == 0 {
+			return fmt.Errorf("%w: seat %d won on %s, which it does not have", ErrInconsistent, seat, mj.FormatTile(tile))
+		}
+	} else {
+		if r.lastFrom < 0 || from != r.lastFrom || tile != r.last {
+			return fmt.Errorf("%w: seat %d won on %s from seat %d, which is not the latest discard",
+				ErrInconsistent, seat, mj.FormatTile(tile), from)
+		}
+		if len(hand)%3 != 1 {
+			return fmt.Errorf("%w: seat %d has %d tiles", ErrInconsistent, seat, len(hand))
+		}
+		hand = hand.Append(tile)
+	}
+	if handcheck.Shanten(hand, t.Rules) != -1 {
+		return fmt.Errorf("%w: seat %d won with %s, which is not complete", ErrInconsistent, seat, mj.FormatHand(hand))
+	}
+	sort.Sort(hand)
+	t.Hands[seat] = hand
+	t.Winner, t.Over = seat, true
+	return nil
+}
+
+// take removes a tile from the seat's hand, and returns false if it is not there.
+func (r *Replay) take(seat int, tile mj.Tile) bool {
+	for i, ht := range r.t.Hands[seat] {
+		if ht == tile {
+			r.t.Hands[seat] = r.t.Hands[seat].Remove(i)
+			return true
+		}
+	}
+	return false
+}
+
+func parseHand(s string) (mj.Hand, error) {
+	if s == "" {
+		return mj.Hand{}, nil
+	}
+	return mj.ParseHand(s)
+}
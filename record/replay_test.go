@@ -0,0 +1,157 @@
+package record
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+// noBonus is played without flowers, so that draws come from the front of the wall.
+var noBonus = mj.Ruleset{Name: "no bonus", Tiles: mj.TileSet{NoFlowers: true, NoSeasons: true}}
+
+// opening returns the start entry of a game on noBonus, and the wall after the deal.
+func opening(seed int64) (Entry, mj.Hand) {
+	hands, bonus, wall := noBonus.Deal(rand.New(rand.NewSource(seed)))
+	return Start(seed, noBonus, hands, bonus), wall
+}
+
+func TestReplay(t *testing.T) {
+	start, wall := opening(1)
+	f := mj.FormatTile
+	entries := []Entry{
+		start,
+		{Type: TypeDraw, Seat: 0, Tile: f(wall[0])},
+		{Type: TypeDiscard, Seat: 0, Tile: f(wall[0])},
+		{Type: TypeDraw, Seat: 1, Tile: f(wall[1])},
+		{Type: TypeDiscard, Seat: 1, Tile: f(wall[1])},
+	}
+
+	r, err := NewReplay(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb := r.Table()
+	if tb.Live != len(wall) || len(tb.Wall) != len(wall) || tb.Turn != 0 || tb.Winner != -1 {
+		t.Errorf("unexpected start %+v", tb)
+	}
+	for i := 1; i < len(entries); i++ {
+		if _, err := r.Next(); err != nil {
+			t.Fatalf("entry %d: %v", i, err)
+		}
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Next() at end = %v, want io.EOF", err)
+	}
+
+	tb, err = At(entries, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tb.Hands[0]) != 14 || tb.Live != len(wall)-1 || tb.Wall[0] != wall[1] {
+		t.Errorf("At(1) = %+v", tb)
+	}
+	tb, err = Verify(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tb.Step != 4 || tb.Turn != 2 || len(tb.Hands[1]) != 13 ||
+		len(tb.Discards[1]) != 1 || tb.Discards[1][0] != wall[1] {
+		t.Errorf("Verify() = %+v", tb)
+	}
+	if _, err := At(entries, 5); err == nil {
+		t.Error("At() past the end should fail")
+	}
+}
+
+func TestReplay_Inconsistent(t *testing.T) {
+	start, wall := opening(1)
+	f := mj.FormatTile
+	hand := mj.MustParseHand(start.Hands[0])
+	var missing mj.Tile
+	for _, u := range noBonus.Tiles.Unique() {
+		if hand.ToCount().Get(u) == 0 && u != wall[0] {
+			missing = u
+			break
+		}
+	}
+
+	for _, c := range []struct {
+		name    string
+		entries []Entry
+	}{
+		{"discard before draw", []Entry{
+			{Type: TypeDiscard, Seat: 0, Tile: start.Hands[0][:2]},
+		}},
+		{"wrong turn", []Entry{
+			{Type: TypeDraw, Seat: 1, Tile: f(wall[0])},
+		}},
+		{"wrong tile drawn", []Entry{
+			{Type: TypeDraw, Seat: 0, Tile: f(wall[1])},
+		}},
+		{"discard not in hand", []Entry{
+			{Type: TypeDraw, Seat: 0, Tile: f(wall[0])},
+			{Type: TypeDiscard, Seat: 0, Tile: f(missing)},
+		}},
+		{"claim without the tiles", []Entry{
+			{Type: TypeDraw, Seat: 0, Tile: f(wall[0])},
+			{Type: TypeDiscard, Seat: 0, Tile: f(wall[0])},
+			{Type: TypeMeld, Seat: 2, From: 0, Tile: f(wall[0]), Meld: FormatMeld(mj.Meld{Kind: mj.Gang, Tile: wall[0]})},
+		}},
+		{"claim after a draw", []Entry{
+			{Type: TypeDraw, Seat: 0, Tile: f(wall[0])},
+			{Type: TypeDiscard, Seat: 0, Tile: f(wall[0])},
+			{Type: TypeDraw, Seat: 1, Tile: f(wall[1])},
+			{Type: TypeMeld, Seat: 2, From: 0, Tile: f(wall[0]), Meld: FormatMeld(mj.Meld{Kind: mj.Peng, Tile: wall[0]})},
+		}},
+		{"incomplete win", []Entry{
+			{Type: TypeDraw, Seat: 0, Tile: f(wall[0])},
+			{Type: TypeWin, Seat: 0, From: 0, Tile: f(wall[0])},
+		}},
+		{"exhausted early", []Entry{
+			{Type: TypeExhausted},
+		}},
+		{"unknown type", []Entry{
+			{Type: "chat"},
+		}},
+	} {
+		_, err := Verify(append([]Entry{start}, c.entries...))
+		if !errors.Is(err, ErrInconsistent) {
+			t.Errorf("%s: Verify() = %v, want ErrInconsistent", c.name, err)
+		}
+	}
+}
+
+func TestReplay_Start(t *testing.T) {
+	start, wall := opening(1)
+
+	// hands that were not dealt from the seed still replay, without the wall
+	other := start
+	other.Seed = 2
+	r, err := NewReplay([]Entry{other, {Type: TypeDraw, Seat: 0, Tile: mj.FormatTile(wall[5])}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Table().Wall != nil {
+		t.Error("wall should not be rebuilt for another seed")
+	}
+	if _, err := r.Next(); err != nil {
+		t.Error(err)
+	}
+
+	bad := start
+	bad.Hands = append([]string{"b1 b1 b1 b1 b1 b2 b3 b4 b5 b6 b7 b8 b9"}, start.Hands[1:]...)
+	if _, err := NewReplay([]Entry{bad}); !errors.Is(err, ErrInconsistent) {
+		t.Errorf("NewReplay() with five b1 = %v, want ErrInconsistent", err)
+	}
+	short := start
+	short.Hands = start.Hands[1:]
+	if _, err := NewReplay([]Entry{short}); !errors.Is(err, ErrInconsistent) {
+		t.Errorf("NewReplay() with 3 hands = %v, want ErrInconsistent", err)
+	}
+	if _, err := NewReplay([]Entry{{Type: TypeDraw}}); !errors.Is(err, ErrInconsistent) {
+		t.Errorf("NewReplay() without start = %v, want ErrInconsistent", err)
+	}
+}
@@ -0,0 +1,161 @@
+package mj
+
+// Ruleset describes the parts of a game's rules that affect how hands are analysed.
+// The zero Ruleset is the basic Hong Kong ruleset. The score package is the exception:
+// there, a zero Ruleset in score.Win means that none was given, and each scorer uses its
+// own preset instead. IsZero tells the two apart.
+type Ruleset struct {
+	Name string
+	// HandSize is the number of tiles in a waiting hand, not counting bonus tiles and
+	// counting each gang as 3 tiles. A winning hand has one more tile. It must be one
+	// more than a multiple of 3. Zero means 13.
+	HandSize int
+	// NumPlayers is the number of players at the table. Zero means 4.
+	NumPlayers int
+	// NoChi forbids chis, both claimed and formed in the hand.
+	NoChi bool
+	// Tiles is the set of tiles that the game is played with.
+	Tiles TileSet
+
+	// Specials lists the special hands that are allowed to win. Zero means the usual
+	// special hands for the hand size: thirteen orphans and seven pairs for 13-tile hands,
+	// or seven pairs and a pung for 16-tile hands.
+	Specials SpecialHands
+	// SevenPairsRepeat allows four identical tiles to count as two of the seven pairs.
+	SevenPairsRepeat bool
+
+	// Scoring names the scoring table used to score winning hands. See score.For for
+	// the names that are understood. Empty means that hands are not scored.
+	Scoring string
+}
+
+// SpecialHands is a set of special hands that do not follow the usual form of
+// sets and a pair.
+type SpecialHands uint8
+
+const (
+	// ThirteenOrphansHand is one of each terminal and honour, plus a pair of any of them.
+	ThirteenOrphansHand SpecialHands = 1 << iota
+	// SevenPairsHand is seven pairs in a 13-tile game, or seven pairs and a pung in a
+	// 16-tile game.
+	SevenPairsHand
+	// HonoursAndKnittedHand is 14 different tiles taken from a knitted straight and the
+	// seven honours.
+	HonoursAndKnittedHand
+	// KnittedStraightHand is a knitted straight of 147, 258 and 369 in different suits,
+	// with a set and a pair.
+	KnittedStraightHand
+
+	// NoSpecialHands allows no special hands at all.
+	NoSpecialHands SpecialHands = 1 << 7
+)
+
+var (
+	// HongKong is the basic 13-tile ruleset.
+	HongKong = Ruleset{Name: "Hong Kong", HandSize: 13}
+	// Taiwanese is the 16-tile ruleset, where a winning hand is five sets and a pair.
+	Taiwanese = Ruleset{Name: "Taiwanese", HandSize: 16, Scoring: "taiwanese"}
+	// Sanma is the three-player ruleset. Wan 2 to 8 and the bonus tiles are removed,
+	// and chis are not allowed.
+	Sanma = Ruleset{
+		Name:       "Sanma",
+		HandSize:   13,
+		NumPlayers: 3,
+		NoChi:      true,
+		Tiles: TileSet{
+			NoFlowers: true,
+			NoSeasons: true,
+			RedFives:  1,
+			Removed: Hand{
+				{Wan, 2}, {Wan, 3}, {Wan, 4}, {Wan, 5}, {Wan, 6}, {Wan, 7}, {Wan, 8},
+			},
+		},
+		Scoring: "riichi",
+	}
+	// Singapore adds the animal tiles to the Hong Kong tileset.
+	Singapore = Ruleset{
+		Name:     "Singapore",
+		HandSize: 13,
+		Tiles:    TileSet{Animals: true},
+		Specials: ThirteenOrphansHand,
+		Scoring:  "singapore",
+	}
+	// Riichi is the Japanese ruleset, played without bonus tiles.
+	Riichi = Ruleset{
+		Name:     "Riichi",
+		HandSize: 13,
+		Tiles:    TileSet{NoFlowers: true, NoSeasons: true, RedFives: 1},
+		Scoring:  "riichi",
+	}
+	// MCR is the Chinese Official ruleset.
+	MCR = Ruleset{
+		Name:             "Chinese Official",
+		HandSize:         13,
+		Specials:         ThirteenOrphansHand | SevenPairsHand | HonoursAndKnittedHand | KnittedStraightHand,
+		SevenPairsRepeat: true,
+		Scoring:          "mcr",
+	}
+)
+
+// Presets returns the built-in rulesets.
+func Presets() []Ruleset {
+	return []Ruleset{HongKong, Taiwanese, Sanma, Singapore, Riichi, MCR}
+}
+
+// Size returns the number of tiles in a waiting hand.
+func (r Ruleset) Size() int {
+	if r.HandSize == 0 {
+		return 13
+	}
+	return r.HandSize
+}
+
+// Sets returns the number of 3-tile sets in a winning hand, in addition to the pair.
+func (r Ruleset) Sets() int {
+	return (r.Size() - 1) / 3
+}
+
+// IsZero returns true if r is the zero Ruleset.
+func (r Ruleset) IsZero() bool {
+	return r.Name == "" && r.HandSize == 0 && r.NumPlayers == 0 && !r.NoChi &&
+		r.Tiles.IsZero() && r.Specials == 0 && !r.SevenPairsRepeat && r.Scoring == ""
+}
+
+// Players returns the number of players at the table.
+func (r Ruleset) Players() int {
+	if r.NumPlayers == 0 {
+		return 4
+	}
+	return r.NumPlayers
+}
+
+// Allows returns true if all of the special hands are allowed to win.
+func (r Ruleset) Allows(s SpecialHands) bool {
+	specials := r.Specials
+	if specials == 0 {
+		switch r.Size() {
+		case 13:
+			specials = ThirteenOrphansHand | SevenPairsHand
+		case 16:
+			specials = SevenPairsHand
+		}
+	}
+	return specials&NoSpecialHands == 0 && specials&s == s
+}
+
+// InPlay returns true if the tile is in the Ruleset's tileset.
+func (r Ruleset) InPlay(t Tile) bool {
+	return r.Tiles.Contains(t)
+}
+
+// HandInPlay returns true if every tile in the hand is in play, and the hand does not
+// have more copies of a tile than the tileset. A hand that contains a chi is not in
+// play if chis are forbidden, but HandInPlay does not check this.
+func (r Ruleset) HandInPlay(h Hand) bool {
+	return r.Tiles.CheckHand(h) == nil
+}
+
+// NewCounterAtStart creates a new Counter with every tile in the tileset.
+func (r Ruleset) NewCounterAtStart() Counter {
+	return r.Tiles.NewCounter()
+}
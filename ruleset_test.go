@@ -0,0 +1,14 @@
+package mj
+
+import "testing"
+
+func TestRuleset_IsZero(t *testing.T) {
+	if !(Ruleset{}).IsZero() {
+		t.Error("zero Ruleset is not zero")
+	}
+	for _, rs := range append(Presets(), Ruleset{NoChi: true}, Ruleset{Tiles: TileSet{Removed: Hand{{Wan, 2}}}}) {
+		if rs.IsZero() {
+			t.Errorf("%+v is zero", rs)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+// Package score contains scorers for winning hands under several rulesets.
+//
+// A scorer takes a Win, which describes the winning hand and the circumstances of the
+// win, and returns a Breakdown of the points earned. Every interpretation of the hand is
+// considered and the highest scoring one is returned. Scorers do not check whether the
+// game could actually have reached the described state.
+package score
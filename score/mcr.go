@@ -0,0 +1,840 @@
+package score
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+	"github.com/nik0sc/mj/special"
+	"github.com/nik0sc/mj/wait"
+)
+
+// ErrBelowMinimum is returned when a hand is complete but does not score enough to win.
+var ErrBelowMinimum = errors.New("below the minimum score")
+
+// MCRMinimum is the minimum fan needed to win under Chinese Official rules, not
+// counting flowers.
+const MCRMinimum = 8
+
+// mcrFans holds the value of each of the 81 fans in the Chinese Official rules.
+var mcrFans = map[string]int{
+	"Big Four Winds":                    88,
+	"Big Three Dragons":                 88,
+	"All Green":                         88,
+	"Nine Gates":                        88,
+	"Four Kongs":                        88,
+	"Seven Shifted Pairs":               88,
+	"Thirteen Orphans":                  88,
+	"All Terminals":                     64,
+	"Little Four Winds":                 64,
+	"Little Three Dragons":              64,
+	"All Honours":                       64,
+	"Four Concealed Pungs":              64,
+	"Pure Terminal Chows":               64,
+	"Quadruple Chow":                    48,
+	"Four Pure Shifted Pungs":           48,
+	"Four Pure Shifted Chows":           32,
+	"Three Kongs":                       32,
+	"All Terminals and Honours":         32,
+	"Seven Pairs":                       24,
+	"Greater Honours and Knitted Tiles": 24,
+	"All Even Pungs":                    24,
+	"Full Flush":                        24,
+	"Pure Triple Chow":                  24,
+	"Pure Shifted Pungs":                24,
+	"Upper Tiles":                       24,
+	"Middle Tiles":                      24,
+	"Lower Tiles":                       24,
+	"Pure Straight":                     16,
+	"Three-Suited Terminal Chows":       16,
+	"Pure Shifted Chows":                16,
+	"All Fives":                         16,
+	"Triple Pung":                       16,
+	"Three Concealed Pungs":             16,
+	"Lesser Honours and Knitted Tiles":  12,
+	"Knitted Straight":                  12,
+	"Upper Four":                        12,
+	"Lower Four":                        12,
+	"Big Three Winds":                   12,
+	"Mixed Straight":                    8,
+	"Reversible Tiles":                  8,
+	"Mixed Triple Chow":                 8,
+	"Mixed Shifted Pungs":               8,
+	"Chicken Hand":                      8,
+	"Last Tile Draw":                    8,
+	"Last Tile Claim":                   8,
+	"Out with Replacement Tile":         8,
+	"Robbing the Kong":                  8,
+	"All Pungs":                         6,
+	"Half Flush":                        6,
+	"Mixed Shifted Chows":               6,
+	"All Types":                         6,
+	"Melded Hand":                       6,
+	"Two Dragon Pungs":                  6,
+	"Two Concealed Kongs":               6,
+	"Outside Hand":                      4,
+	"Fully Concealed Hand":              4,
+	"Two Melded Kongs":                  4,
+	"Last Tile":                         4,
+	"Dragon Pung":                       2,
+	"Prevalent Wind":                    2,
+	"Seat Wind":                         2,
+	"Concealed Hand":                    2,
+	"All Chows":                         2,
+	"Tile Hog":                          2,
+	"Double Pung":                       2,
+	"Two Concealed Pungs":               2,
+	"Concealed Kong":                    2,
+	"All Simples":                       2,
+	"Pure Double Chow":                  1,
+	"Mixed Double Chow":                 1,
+	"Short Straight":                    1,
+	"Two Terminal Chows":                1,
+	"Pung of Terminals or Honours":      1,
+	"Melded Kong":                       1,
+	"One Voided Suit":                   1,
+	"No Honours":                        1,
+	"Edge Wait":                         1,
+	"Closed Wait":                       1,
+	"Single Wait":                       1,
+	"Self-Drawn":                        1,
+	"Flower Tiles":                      1,
+}
+
+// mcrExcludes holds the fans that are implied by another fan, and so are not
+// counted when that fan is present.
+var mcrExcludes = map[string][]string{
+	"Big Four Winds": {"Big Three Winds", "Little Four Winds", "All Pungs", "Prevalent Wind",
+		"Seat Wind", "Pung of Terminals or Honours"},
+	"Big Three Dragons": {"Little Three Dragons", "Two Dragon Pungs", "Dragon Pung"},
+	"All Green":         {"Half Flush", "Full Flush"},
+	"Nine Gates": {"Full Flush", "Concealed Hand", "Pung of Terminals or Honours", "No Honours",
+		"One Voided Suit", "Two Concealed Pungs"},
+	"Four Kongs": {"Three Kongs", "Two Melded Kongs", "Two Concealed Kongs", "Melded Kong",
+		"Concealed Kong", "All Pungs", "Single Wait"},
+	"Seven Shifted Pairs": {"Seven Pairs", "Full Flush", "Concealed Hand", "Single Wait",
+		"No Honours", "One Voided Suit"},
+	"Thirteen Orphans": {"All Types", "Concealed Hand", "Single Wait"},
+	"All Terminals": {"All Terminals and Honours", "All Pungs", "Outside Hand",
+		"Pung of Terminals or Honours", "No Honours", "Double Pung"},
+	"Little Four Winds":    {"Big Three Winds", "Pung of Terminals or Honours"},
+	"Little Three Dragons": {"Two Dragon Pungs", "Dragon Pung"},
+	"All Honours": {"All Terminals and Honours", "All Pungs", "Outside Hand",
+		"Pung of Terminals or Honours"},
+	"Four Concealed Pungs": {"Three Concealed Pungs", "Two Concealed Pungs", "All Pungs",
+		"Concealed Hand"},
+	"Pure Terminal Chows": {"Full Flush", "All Chows", "Pure Double Chow", "Two Terminal Chows",
+		"No Honours", "One Voided Suit"},
+	"Quadruple Chow":          {"Pure Triple Chow", "Pure Shifted Pungs", "Pure Double Chow", "Tile Hog"},
+	"Four Pure Shifted Pungs": {"Pure Shifted Pungs", "All Pungs", "Double Pung"},
+	"Four Pure Shifted Chows": {"Pure Shifted Chows", "Pure Double Chow", "Short Straight"},
+	"Three Kongs": {"Two Melded Kongs", "Two Concealed Kongs", "Melded Kong",
+		"Concealed Kong"},
+	"All Terminals and Honours": {"All Pungs", "Outside Hand", "Pung of Terminals or Honours"},
+	"Seven Pairs":               {"Concealed Hand", "Single Wait"},
+	"Greater Honours and Knitted Tiles": {"Lesser Honours and Knitted Tiles", "All Types",
+		"Concealed Hand", "Single Wait"},
+	"All Even Pungs":   {"All Pungs", "All Simples", "No Honours"},
+	"Full Flush":       {"Half Flush", "No Honours", "One Voided Suit"},
+	"Pure Triple Chow": {"Pure Shifted Pungs", "Pure Double Chow"},
+	"Upper Tiles":      {"Upper Four", "No Honours"},
+	"Middle Tiles":     {"All Simples", "No Honours"},
+	"Lower Tiles":      {"Lower Four", "No Honours"},
+	"Pure Straight":    {"Short Straight", "Two Terminal Chows"},
+	"Three-Suited Terminal Chows": {"All Chows", "Mixed Double Chow", "Two Terminal Chows",
+		"No Honours"},
+	"All Fives":                        {"All Simples", "No Honours"},
+	"Three Concealed Pungs":            {"Two Concealed Pungs"},
+	"Lesser Honours and Knitted Tiles": {"All Types", "Concealed Hand", "Single Wait"},
+	"Upper Four":                       {"No Honours"},
+	"Lower Four":                       {"No Honours"},
+	"Big Three Winds":                  {"Pung of Terminals or Honours"},
+	"Mixed Triple Chow":                {"Mixed Double Chow"},
+	"Reversible Tiles":                 {"One Voided Suit"},
+	"Last Tile Draw":                   {"Self-Drawn"},
+	"Out with Replacement Tile":        {"Self-Drawn"},
+	"Robbing the Kong":                 {"Last Tile"},
+	"Melded Hand":                      {"Single Wait"},
+	"Two Dragon Pungs":                 {"Dragon Pung"},
+	"Two Concealed Kongs":              {"Concealed Kong", "Two Concealed Pungs"},
+	"Fully Concealed Hand":             {"Self-Drawn", "Concealed Hand"},
+	"Two Melded Kongs":                 {"Melded Kong"},
+	"All Chows":                        {"No Honours"},
+	"All Simples":                      {"No Honours"},
+}
+
+// MCRScorer scores hands in fan according to the Chinese Official (Mahjong Competition
+// Rules) ruleset, which has 81 fans. The zero value is ready to use.
+//
+// Fans that are implied by a higher fan are excluded. Pairs of chows are only counted once
+// per chow, and not between chows that already score together as a larger pattern. If the
+// hand does not reach MCRMinimum, the breakdown is returned with ErrBelowMinimum.
+type MCRScorer struct{}
+
+// Score finds the highest scoring interpretation of a winning hand.
+func (s MCRScorer) Score(w Win) (Breakdown, error) {
+	w = w.withRules(mj.MCR)
+	if err := w.validate(13); err != nil {
+		return Breakdown{}, err
+	}
+
+	as := append(w.arrangements(), mcrArrangements(w)...)
+	waits := completingTiles(w)
+
+	b, err := best(as, func(a arrangement) (Breakdown, bool) {
+		return s.score(w, a, waits), true
+	})
+	if err != nil {
+		return Breakdown{}, err
+	}
+
+	flowers := 0
+	for _, it := range b.Items {
+		if it.Name == "Flower Tiles" {
+			flowers += it.Points
+		}
+	}
+	if b.Total-flowers < MCRMinimum {
+		return b, fmt.Errorf("%w: %d fan", ErrBelowMinimum, b.Total-flowers)
+	}
+	return b, nil
+}
+
+// mcrArrangements finds the interpretations of a hand that only exist in Chinese Official
+// rules: knitted straights and honours and knitted tiles.
+func mcrArrangements(w Win) []arrangement {
+	var out []arrangement
+	tiles := w.allTiles()
+
+	if len(w.Melds) == 0 && w.Rules.Allows(mj.HonoursAndKnittedHand) {
+		if ok, greater := special.IsHonoursAndKnitted(w.Concealed); ok {
+			name := "Lesser Honours and Knitted Tiles"
+			if greater {
+				name = "Greater Honours and Knitted Tiles"
+			}
+			out = append(out, arrangement{special: name, win: -1, tiles: tiles})
+		}
+	}
+
+	if !w.Rules.Allows(mj.KnittedStraightHand) {
+		return out
+	}
+	if ok, rest := special.FindKnittedStraight(w.Concealed); ok {
+		for _, g := range handcheck.CompleteWithRules(rest, w.Rules) {
+			a := arrangement{special: "Knitted Straight", pair: g.Pairs[0], win: -1, tiles: tiles}
+			a.sets = append(a.sets, w.Melds...)
+			for _, t := range g.Pengs {
+				a.sets = append(a.sets, mj.Meld{Kind: mj.Peng, Tile: t, Concealed: true})
+			}
+			for _, t := range g.Chis {
+				a.sets = append(a.sets, mj.Meld{Kind: mj.Chi, Tile: t, Concealed: true})
+			}
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// completingTiles returns the number of different tiles that would have completed the
+// hand, in the usual form of sets and a pair under any grouping, or as a special hand
+// allowed by the Ruleset.
+func completingTiles(w Win) int {
+	h := w.Concealed.Remove(indexOf(w.Concealed, w.WinningTile))
+	found := make(map[mj.Tile]bool)
+	for _, wt := range wait.Classify(h, w.Rules) {
+		found[wt.Tile] = true
+	}
+	for _, t := range special.Waits(h, w.Rules) {
+		found[t] = true
+	}
+	return len(found)
+}
+
+func (MCRScorer) score(w Win, a arrangement, waits int) Breakdown {
+	var fans []string
+	add := func(names ...string) {
+		fans = append(fans, names...)
+	}
+
+	switch a.special {
+	case "thirteen orphans":
+		add("Thirteen Orphans")
+	case "seven pairs":
+		if isShiftedPairs(a.tiles) {
+			add("Seven Shifted Pairs")
+		} else {
+			add("Seven Pairs")
+		}
+	case "":
+	default:
+		add(a.special)
+	}
+
+	add(mcrTileFans(w, a)...)
+	if len(a.sets) > 0 {
+		add(mcrSetFans(w, a)...)
+	}
+	if a.special == "" && waits == 1 {
+		switch a.wait {
+		case wait.Edge:
+			add("Edge Wait")
+		case wait.Closed:
+			add("Closed Wait")
+		case wait.Single:
+			add("Single Wait")
+		}
+	}
+	add(mcrSituationFans(w)...)
+
+	excluded := make(map[string]bool)
+	for _, f := range fans {
+		for _, ex := range mcrExcludes[f] {
+			excluded[ex] = true
+		}
+	}
+
+	var b Breakdown
+	for _, f := range fans {
+		if !excluded[f] {
+			b.add(f, mcrFans[f])
+		}
+	}
+	if b.Total == 0 {
+		b.add("Chicken Hand", mcrFans["Chicken Hand"])
+	}
+	for range w.Bonus {
+		b.add("Flower Tiles", mcrFans["Flower Tiles"])
+	}
+
+	sort.SliceStable(b.Items, func(i, j int) bool {
+		return b.Items[i].Points > b.Items[j].Points
+	})
+	return b
+}
+
+// mcrTileFans finds the fans that only depend on which tiles are in the hand.
+func mcrTileFans(w Win, a arrangement) []string {
+	var fans []string
+	ss, honours := suits(a.tiles)
+
+	allIn := func(f func(t mj.Tile) bool) bool {
+		for _, t := range a.tiles {
+			if !f(t) {
+				return false
+			}
+		}
+		return true
+	}
+	valueIn := func(lo, hi mj.Value) func(t mj.Tile) bool {
+		return func(t mj.Tile) bool {
+			return t.IsBasic() && lo <= t.Value && t.Value <= hi
+		}
+	}
+
+	green := mj.MustParseHand("b2 b3 b4 b6 b8 hf").ToCount()
+	if allIn(func(t mj.Tile) bool { return green.Get(t) > 0 }) {
+		fans = append(fans, "All Green")
+	}
+	reversible := mj.MustParseHand("c1 c2 c3 c4 c5 c8 c9 b2 b4 b5 b6 b8 b9 hb").ToCount()
+	if allIn(func(t mj.Tile) bool { return reversible.Get(t) > 0 }) {
+		fans = append(fans, "Reversible Tiles")
+	}
+
+	if a.special == "" || a.special == "seven pairs" {
+		switch {
+		case allIn(mj.Tile.IsTerminal):
+			fans = append(fans, "All Terminals")
+		case allIn(isOrphan) && !allIn(isHonour):
+			fans = append(fans, "All Terminals and Honours")
+		}
+	}
+	if allIn(isHonour) {
+		fans = append(fans, "All Honours")
+	}
+
+	switch {
+	case len(ss) == 1 && !honours:
+		fans = append(fans, "Full Flush")
+		if isNineGates(w, a) {
+			fans = append(fans, "Nine Gates")
+		}
+	case len(ss) == 1:
+		fans = append(fans, "Half Flush")
+	case len(ss) == 2:
+		fans = append(fans, "One Voided Suit")
+	case len(ss) == 3 && a.special == "" && hasAllTypes(a):
+		fans = append(fans, "All Types")
+	}
+
+	switch {
+	case allIn(valueIn(7, 9)):
+		fans = append(fans, "Upper Tiles")
+	case allIn(valueIn(4, 6)):
+		fans = append(fans, "Middle Tiles")
+	case allIn(valueIn(1, 3)):
+		fans = append(fans, "Lower Tiles")
+	case allIn(valueIn(6, 9)):
+		fans = append(fans, "Upper Four")
+	case allIn(valueIn(1, 4)):
+		fans = append(fans, "Lower Four")
+	}
+	if allIn(valueIn(2, 8)) {
+		fans = append(fans, "All Simples")
+	}
+	if !honours {
+		fans = append(fans, "No Honours")
+	}
+
+	// four of a tile that are not a gang
+	gangs := make(map[mj.Tile]bool)
+	for _, m := range a.sets {
+		if m.Kind == mj.Gang {
+			gangs[m.Tile] = true
+		}
+	}
+	a.tiles.ToCount().ForEach(func(t mj.Tile, n int) bool {
+		if n == 4 && !gangs[t] {
+			fans = append(fans, "Tile Hog")
+		}
+		return true
+	})
+
+	return fans
+}
+
+func isHonour(t mj.Tile) bool {
+	return t.Suit == mj.Honour
+}
+
+// hasAllTypes returns true if every set and the pair together use all three suits,
+// winds and dragons.
+func hasAllTypes(a arrangement) bool {
+	wind, dragon := isWind(a.pair), isDragon(a.pair)
+	for _, m := range a.sets {
+		wind = wind || isWind(m.Tile)
+		dragon = dragon || isDragon(m.Tile)
+	}
+	return wind && dragon
+}
+
+func isShiftedPairs(tiles mj.Hand) bool {
+	// tiles is sorted, so pairs are adjacent
+	for i := 2; i < len(tiles); i += 2 {
+		if !tiles[i].IsBasic() || tiles[i].Suit != tiles[0].Suit || tiles[i].Value != tiles[i-2].Value+1 {
+			return false
+		}
+	}
+	return true
+}
+
+func isNineGates(w Win, a arrangement) bool {
+	if len(w.Melds) > 0 {
+		return false
+	}
+	// removing the winning tile must leave 1112345678999 in one suit
+	h := w.Concealed.Remove(indexOf(w.Concealed, w.WinningTile))
+	cnt := h.ToCount()
+	for v := mj.Value(1); v <= 9; v++ {
+		want := 1
+		if v == 1 || v == 9 {
+			want = 3
+		}
+		if cnt.Get(mj.Tile{Suit: a.tiles[0].Suit, Value: v}) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// mcrSetFans finds the fans that depend on the sets and the pair.
+func mcrSetFans(w Win, a arrangement) []string {
+	var fans []string
+	var chows mj.Hand
+	var pungs []mj.Meld
+	for _, m := range a.sets {
+		if m.Kind == mj.Chi {
+			chows = append(chows, m.Tile)
+		} else {
+			pungs = append(pungs, m)
+		}
+	}
+	sort.Sort(chows)
+
+	// honours
+	dragons, winds := 0, 0
+	for _, m := range pungs {
+		switch {
+		case isDragon(m.Tile):
+			dragons++
+			fans = append(fans, "Dragon Pung")
+		case isWind(m.Tile):
+			winds++
+			scored := false
+			if m.Tile.Value == w.Round {
+				fans = append(fans, "Prevalent Wind")
+				scored = true
+			}
+			if m.Tile.Value == w.Seat {
+				fans = append(fans, "Seat Wind")
+				scored = true
+			}
+			if !scored {
+				fans = append(fans, "Pung of Terminals or Honours")
+			}
+		case m.Tile.IsTerminal():
+			fans = append(fans, "Pung of Terminals or Honours")
+		}
+	}
+	switch {
+	case dragons == 3:
+		fans = append(fans, "Big Three Dragons")
+	case dragons == 2 && isDragon(a.pair):
+		fans = append(fans, "Little Three Dragons")
+	case dragons == 2:
+		fans = append(fans, "Two Dragon Pungs")
+	}
+	switch {
+	case winds == 4:
+		fans = append(fans, "Big Four Winds")
+	case winds == 3 && isWind(a.pair):
+		fans = append(fans, "Little Four Winds")
+	case winds == 3:
+		fans = append(fans, "Big Three Winds")
+	}
+
+	// pungs and kongs
+	concealedPungs, meldedKongs, concealedKongs := 0, 0, 0
+	for _, m := range pungs {
+		if m.Concealed {
+			concealedPungs++
+		}
+		if m.Kind == mj.Gang {
+			if m.Concealed {
+				concealedKongs++
+			} else {
+				meldedKongs++
+			}
+		}
+	}
+	switch concealedPungs {
+	case 4:
+		fans = append(fans, "Four Concealed Pungs")
+	case 3:
+		fans = append(fans, "Three Concealed Pungs")
+	case 2:
+		fans = append(fans, "Two Concealed Pungs")
+	}
+	switch kongs := meldedKongs + concealedKongs; {
+	case kongs == 4:
+		fans = append(fans, "Four Kongs")
+	case kongs == 3:
+		fans = append(fans, "Three Kongs")
+	case concealedKongs == 2:
+		fans = append(fans, "Two Concealed Kongs")
+	case meldedKongs == 2:
+		fans = append(fans, "Two Melded Kongs")
+	default:
+		for i := 0; i < concealedKongs; i++ {
+			fans = append(fans, "Concealed Kong")
+		}
+		for i := 0; i < meldedKongs; i++ {
+			fans = append(fans, "Melded Kong")
+		}
+	}
+
+	if len(pungs) == 4 {
+		fans = append(fans, "All Pungs")
+		if a.pair.IsBasic() && a.pair.Value%2 == 0 && allEven(pungs) {
+			fans = append(fans, "All Even Pungs")
+		}
+	}
+	fans = append(fans, mcrPungPatterns(pungs)...)
+
+	// chows
+	if len(chows) == 4 && a.pair.IsBasic() {
+		fans = append(fans, "All Chows")
+	}
+	fans = append(fans, mcrChowPatterns(chows, a.pair)...)
+
+	// whole hand
+	outside, fives := true, a.pair.Value == 5 && a.pair.IsBasic()
+	if !isOrphan(a.pair) {
+		outside = false
+	}
+	for _, m := range a.sets {
+		hasOrphan, hasFive := false, false
+		for _, t := range m.Tiles() {
+			hasOrphan = hasOrphan || isOrphan(t)
+			hasFive = hasFive || t.IsBasic() && t.Value == 5
+		}
+		outside = outside && hasOrphan
+		fives = fives && hasFive
+	}
+	if outside && len(a.sets) == 4 {
+		fans = append(fans, "Outside Hand")
+	}
+	if fives && len(a.sets) == 4 {
+		fans = append(fans, "All Fives")
+	}
+
+	exposed := 0
+	for _, m := range w.Melds {
+		if !m.Concealed {
+			exposed++
+		}
+	}
+	if exposed == 4 && !w.SelfDrawn {
+		fans = append(fans, "Melded Hand")
+	}
+
+	return fans
+}
+
+func allEven(pungs []mj.Meld) bool {
+	for _, m := range pungs {
+		if !m.Tile.IsBasic() || m.Tile.Value%2 != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// mcrPungPatterns finds patterns between numbered pungs in the same or different suits.
+func mcrPungPatterns(pungs []mj.Meld) []string {
+	var ts mj.Hand
+	for _, m := range pungs {
+		if m.Tile.IsBasic() {
+			ts = append(ts, m.Tile)
+		}
+	}
+	sort.Sort(ts)
+
+	if len(ts) == 4 && sameSuit(ts...) && stepped(1, ts...) {
+		return []string{"Four Pure Shifted Pungs"}
+	}
+
+	var fans []string
+	used := make(map[int]bool)
+	forCombos(len(ts), 3, func(idx []int) bool {
+		a, b, c := ts[idx[0]], ts[idx[1]], ts[idx[2]]
+		switch {
+		case sameSuit(a, b, c) && stepped(1, a, b, c):
+			fans = append(fans, "Pure Shifted Pungs")
+		case differentSuits(a, b, c) && a.Value == b.Value && b.Value == c.Value:
+			fans = append(fans, "Triple Pung")
+		case differentSuits(a, b, c) && steppedAnyOrder(1, a, b, c):
+			fans = append(fans, "Mixed Shifted Pungs")
+		default:
+			return true
+		}
+		for _, i := range idx {
+			used[i] = true
+		}
+		return false
+	})
+	if len(fans) > 0 {
+		return fans
+	}
+
+	forCombos(len(ts), 2, func(idx []int) bool {
+		a, b := ts[idx[0]], ts[idx[1]]
+		if a.Suit != b.Suit && a.Value == b.Value && !used[idx[0]] && !used[idx[1]] {
+			fans = append(fans, "Double Pung")
+			used[idx[0]], used[idx[1]] = true, true
+		}
+		return true
+	})
+	return fans
+}
+
+// mcrChowPatterns finds patterns between chows, each given by its first tile in sorted
+// order. Chows that make up a larger pattern are not paired with each other, and each
+// chow is only paired once.
+func mcrChowPatterns(chows mj.Hand, pair mj.Tile) []string {
+	var fans []string
+	grouped := make(map[int]bool)
+
+	if len(chows) == 4 {
+		switch {
+		case chows[0] == chows[3]:
+			return []string{"Quadruple Chow"}
+		case sameSuit(chows...) && (stepped(1, chows...) || stepped(2, chows...)):
+			return []string{"Four Pure Shifted Chows"}
+		}
+	}
+
+	if len(chows) == 4 && pair.IsBasic() && pair.Value == 5 {
+		// 123 and 789 in two suits, with a pair of 5 in one of them or the third
+		var ones, sevens []mj.Suit
+		for _, t := range chows {
+			if t.Value == 1 {
+				ones = append(ones, t.Suit)
+			} else if t.Value == 7 {
+				sevens = append(sevens, t.Suit)
+			}
+		}
+		if len(ones) == 2 && len(sevens) == 2 {
+			switch {
+			case ones[0] == ones[1] && sevens[0] == sevens[1] && ones[0] == sevens[0] &&
+				pair.Suit == ones[0]:
+				return []string{"Pure Terminal Chows"}
+			case ones[0] != ones[1] && (ones[0] == sevens[0] && ones[1] == sevens[1] ||
+				ones[0] == sevens[1] && ones[1] == sevens[0]) &&
+				pair.Suit != ones[0] && pair.Suit != ones[1]:
+				return []string{"Three-Suited Terminal Chows"}
+			}
+		}
+	}
+
+	forCombos(len(chows), 3, func(idx []int) bool {
+		a, b, c := chows[idx[0]], chows[idx[1]], chows[idx[2]]
+		var name string
+		switch {
+		case a == b && b == c:
+			name = "Pure Triple Chow"
+		case sameSuit(a, b, c) && a.Value == 1 && b.Value == 4 && c.Value == 7:
+			name = "Pure Straight"
+		case sameSuit(a, b, c) && (stepped(1, a, b, c) || stepped(2, a, b, c)):
+			name = "Pure Shifted Chows"
+		case differentSuits(a, b, c) && a.Value == b.Value && b.Value == c.Value:
+			name = "Mixed Triple Chow"
+		case differentSuits(a, b, c) && steppedAnyOrder(3, a, b, c):
+			name = "Mixed Straight"
+		case differentSuits(a, b, c) && steppedAnyOrder(1, a, b, c):
+			name = "Mixed Shifted Chows"
+		default:
+			return true
+		}
+		fans = append(fans, name)
+		for _, i := range idx {
+			grouped[i] = true
+		}
+		return false
+	})
+
+	paired := make(map[int]bool)
+	forCombos(len(chows), 2, func(idx []int) bool {
+		i, j := idx[0], idx[1]
+		if grouped[i] && grouped[j] || paired[i] || paired[j] {
+			return true
+		}
+		a, b := chows[i], chows[j]
+		var name string
+		switch {
+		case a == b:
+			name = "Pure Double Chow"
+		case a.Suit != b.Suit && a.Value == b.Value:
+			name = "Mixed Double Chow"
+		case a.Suit == b.Suit && a.Value+3 == b.Value:
+			name = "Short Straight"
+		case a.Suit == b.Suit && a.Value == 1 && b.Value == 7:
+			name = "Two Terminal Chows"
+		default:
+			return true
+		}
+		fans = append(fans, name)
+		paired[i], paired[j] = true, true
+		return true
+	})
+
+	return fans
+}
+
+func sameSuit(ts ...mj.Tile) bool {
+	for _, t := range ts[1:] {
+		if t.Suit != ts[0].Suit {
+			return false
+		}
+	}
+	return true
+}
+
+func differentSuits(ts ...mj.Tile) bool {
+	seen := make(map[mj.Suit]bool)
+	for _, t := range ts {
+		if seen[t.Suit] {
+			return false
+		}
+		seen[t.Suit] = true
+	}
+	return true
+}
+
+// stepped returns true if each tile's value is step more than the one before it.
+func stepped(step mj.Value, ts ...mj.Tile) bool {
+	for i := 1; i < len(ts); i++ {
+		if ts[i].Value != ts[i-1].Value+step {
+			return false
+		}
+	}
+	return true
+}
+
+func steppedAnyOrder(step mj.Value, ts ...mj.Tile) bool {
+	sorted := make(mj.Hand, len(ts))
+	copy(sorted, ts)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Value < sorted[j].Value
+	})
+	return stepped(step, sorted...)
+}
+
+// forCombos calls f with the indices of each combination of k out of n items, in
+// lexicographic order, until f returns false.
+func forCombos(n, k int, f func(idx []int) bool) {
+	idx := make([]int, k)
+	var rec func(start, depth int) bool
+	rec = func(start, depth int) bool {
+		if depth == k {
+			return f(idx)
+		}
+		for i := start; i < n; i++ {
+			idx[depth] = i
+			if !rec(i+1, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+	rec(0, 0)
+}
+
+// mcrSituationFans finds the fans that depend on how the hand was won.
+func mcrSituationFans(w Win) []string {
+	var fans []string
+
+	exposed := false
+	for _, m := range w.Melds {
+		if !m.Concealed {
+			exposed = true
+		}
+	}
+	switch {
+	case !exposed && w.SelfDrawn:
+		fans = append(fans, "Fully Concealed Hand")
+	case !exposed:
+		fans = append(fans, "Concealed Hand")
+	}
+	if w.SelfDrawn {
+		fans = append(fans, "Self-Drawn")
+	}
+
+	switch {
+	case w.LastWallTile && w.SelfDrawn:
+		fans = append(fans, "Last Tile Draw")
+	case w.LastWallTile:
+		fans = append(fans, "Last Tile Claim")
+	}
+	if w.AfterGang {
+		fans = append(fans, "Out with Replacement Tile")
+	}
+	if w.RobbedGang {
+		fans = append(fans, "Robbing the Kong")
+	}
+	if w.LastTile {
+		fans = append(fans, "Last Tile")
+	}
+	return fans
+}
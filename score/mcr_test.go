@@ -0,0 +1,187 @@
+package score
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestMCRScorer_Score(t *testing.T) {
+	tests := []struct {
+		name      string
+		win       Win
+		wantTotal int
+		wantFan   string
+		wantErr   error
+	}{
+		{
+			"pure straight",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b2 b3 b4 b5 b6 b7 b8 b9 c2 c3 c4 w5 w5"),
+				WinningTile: mustParseTile("w5"),
+			},
+			// pure straight 16, all chows 2, concealed hand 2, single wait 1
+			21,
+			"Pure Straight",
+			nil,
+		},
+		{
+			"below minimum",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b2 b3 c4 c5 c6 w2 w3 w4 b6 b7 b8 c9 c9"),
+				WinningTile: mustParseTile("b8"),
+			},
+			// all chows 2, concealed hand 2, no wait fan for a two-sided wait
+			4,
+			"All Chows",
+			ErrBelowMinimum,
+		},
+		{
+			"thirteen orphans",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b9 c1 c9 w1 w9 he hs hw hn hz hf hb hb"),
+				WinningTile: mustParseTile("hb"),
+				SelfDrawn:   true,
+			},
+			92,
+			"Thirteen Orphans",
+			nil,
+		},
+		{
+			"greater honours and knitted tiles",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b7 c5 c8 w3 w6 w9 he hs hw hn hz hf hb"),
+				Bonus:       mj.MustParseHand("f1 f2"),
+				WinningTile: mustParseTile("hb"),
+				SelfDrawn:   true,
+			},
+			// 24, fully concealed 4, flowers 2
+			30,
+			"Greater Honours and Knitted Tiles",
+			nil,
+		},
+		{
+			"honours and knitted tiles not allowed",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b7 c5 c8 w3 w6 w9 he hs hw hn hz hf hb"),
+				WinningTile: mustParseTile("hb"),
+				SelfDrawn:   true,
+				Rules:       mj.HongKong,
+			},
+			0,
+			"",
+			ErrNotWinning,
+		},
+		{
+			"knitted straight",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b4 b7 c2 c5 c8 w3 w6 w9 hz hz hz he he"),
+				WinningTile: mustParseTile("he"),
+			},
+			16,
+			"Knitted Straight",
+			nil,
+		},
+		{
+			"knitted straight not allowed",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b4 b7 c2 c5 c8 w3 w6 w9 hz hz hz he he"),
+				WinningTile: mustParseTile("he"),
+				Rules:       mj.HongKong,
+			},
+			0,
+			"",
+			ErrNotWinning,
+		},
+		{
+			"knitted straight with a chi, no chis allowed",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b4 b7 c2 c5 c8 w3 w6 w9 b2 b3 b4 he he"),
+				WinningTile: mustParseTile("he"),
+				Rules:       mj.Ruleset{Name: "no chi", HandSize: 13, NoChi: true, Specials: mj.KnittedStraightHand},
+			},
+			0,
+			"",
+			ErrNotWinning,
+		},
+		{
+			"little three dragons",
+			Win{
+				Concealed:   mj.MustParseHand("hz hz hz hf hf b2 b3 b4 c5 c5 c5"),
+				Melds:       []mj.Meld{{Kind: mj.Peng, Tile: mustParseTile("hb")}},
+				WinningTile: mustParseTile("b4"),
+			},
+			// 64, two concealed pungs 2, one voided suit 1
+			67,
+			"Little Three Dragons",
+			nil,
+		},
+		{
+			"nine gates",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b1 b1 b2 b3 b4 b5 b6 b7 b8 b9 b9 b9 b5"),
+				WinningTile: mustParseTile("b5"),
+				SelfDrawn:   true,
+			},
+			92,
+			"Nine Gates",
+			nil,
+		},
+		{
+			"seven shifted pairs",
+			Win{
+				Concealed:   mj.MustParseHand("c2 c2 c3 c3 c4 c4 c5 c5 c6 c6 c7 c7 c8 c8"),
+				WinningTile: mustParseTile("c8"),
+			},
+			// 88, all simples 2
+			90,
+			"Seven Shifted Pairs",
+			nil,
+		},
+		{
+			"mixed triple chow and kongs",
+			Win{
+				Concealed: mj.MustParseHand("b3 b4 b5 c3 c4 c5 w3 w4 w5 hn hn"),
+				Melds: []mj.Meld{
+					{Kind: mj.Gang, Tile: mustParseTile("he"), Concealed: true},
+				},
+				WinningTile: mustParseTile("w5"),
+				SelfDrawn:   true,
+				AfterGang:   true,
+				Seat:        mj.South,
+				Round:       mj.East,
+			},
+			// mixed triple chow 8, replacement tile 8, prevalent wind 2, concealed kong 2,
+			// fully concealed 4
+			24,
+			"Mixed Triple Chow",
+			nil,
+		},
+		{
+			"not winning",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b2 b4 c4 c5 c6 w2 w3 w4 b6 b7 b8 c9 c9"),
+				WinningTile: mustParseTile("b8"),
+			},
+			0,
+			"",
+			ErrNotWinning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MCRScorer{}.Score(tt.win)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Score() err = %v, want %v", err, tt.wantErr)
+			}
+			if got.Total != tt.wantTotal {
+				t.Errorf("Score() = %v, want total %d", got, tt.wantTotal)
+			}
+			if tt.wantFan != "" && (len(got.Items) == 0 || got.Items[0].Name != tt.wantFan) {
+				t.Errorf("Score() = %v, want %s first", got, tt.wantFan)
+			}
+		})
+	}
+}
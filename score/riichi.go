@@ -0,0 +1,510 @@
+package score
+
+import (
+	"fmt"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/wait"
+)
+
+// RiichiScore is the scoring of a winning hand under Japanese riichi rules.
+type RiichiScore struct {
+	// The Breakdown lists every yaku and dora with its han. Total is the han of the hand,
+	// or 13 times the number of yakuman.
+	Breakdown
+	Han, Fu int
+	// Yakuman is the number of yakuman in the hand. Kazoe yakuman counts as 1.
+	Yakuman int
+	// LimitName is the name of the limit reached, like "mangan", if any.
+	LimitName string
+	// Base is the basic points of the hand, before multiplying by the payment factors.
+	Base int
+
+	// On a win by discard, Ron is paid by the discarder. On a self-drawn win, the dealer
+	// pays Dealer (if the winner is not the dealer) and every other player pays NonDealer.
+	Ron, Dealer, NonDealer int
+}
+
+// Points returns the total points received by the winner, not counting honba or riichi sticks.
+func (r RiichiScore) Points() int {
+	switch {
+	case r.Ron > 0:
+		return r.Ron
+	case r.Dealer > 0:
+		return r.Dealer + 2*r.NonDealer
+	}
+	return 3 * r.NonDealer
+}
+
+// RiichiScorer scores hands according to Japanese riichi rules, with the common rules for
+// open tanyao, kazoe yakuman at 13 han and red fives. Winds use Win.Seat and Win.Round, and
+// the dealer is the player in the East seat. Flowers are not used.
+// The zero value is ready to use.
+type RiichiScorer struct{}
+
+// Score finds the highest scoring interpretation of a winning hand and returns its han.
+// Use Points for the payments.
+func (s RiichiScorer) Score(w Win) (Breakdown, error) {
+	r, err := s.Points(w)
+	return r.Breakdown, err
+}
+
+// Points finds the interpretation of a winning hand that pays the most, with the
+// breakdown of yaku, han and fu.
+func (s RiichiScorer) Points(w Win) (RiichiScore, error) {
+	w = w.withRules(mj.Riichi)
+	if err := w.validate(13); err != nil {
+		return RiichiScore{}, err
+	}
+	if len(w.Bonus) > 0 {
+		return RiichiScore{}, fmt.Errorf("%w: bonus tiles are not used in riichi", ErrInvalidHand)
+	}
+
+	var best RiichiScore
+	found := false
+	for _, a := range w.arrangements() {
+		r, ok := s.score(w, a)
+		if !ok {
+			continue
+		}
+		if !found || r.Points() > best.Points() || r.Points() == best.Points() && r.Han > best.Han {
+			best = r
+			found = true
+		}
+	}
+	if !found {
+		return RiichiScore{}, ErrNotWinning
+	}
+	return best, nil
+}
+
+func (RiichiScorer) score(w Win, a arrangement) (RiichiScore, bool) {
+	closed := true
+	for _, m := range w.Melds {
+		if !m.Concealed {
+			closed = false
+		}
+	}
+
+	var r RiichiScore
+
+	yakuman := riichiYakuman(w, a, closed)
+	if len(yakuman) > 0 {
+		for _, y := range yakuman {
+			r.add(y, 13)
+			r.Yakuman++
+		}
+		r.Han = r.Total
+		r.LimitName = "yakuman"
+		r.Base = 8000 * r.Yakuman
+		r.Limit = true
+		r.pay(w)
+		return r, true
+	}
+
+	pinfu := false
+	for _, y := range riichiYaku(w, a, closed) {
+		r.add(y.name, y.han)
+		if y.name == "Pinfu" {
+			pinfu = true
+		}
+	}
+	if r.Total == 0 {
+		// no yaku
+		return r, false
+	}
+
+	dora := 0
+	for _, ind := range w.DoraIndicators {
+		dora += a.tiles.ToCount().Get(doraFromIndicator(ind))
+	}
+	if dora > 0 {
+		r.add("Dora", dora)
+	}
+	if w.Riichi || w.DoubleRiichi {
+		ura := 0
+		for _, ind := range w.UraIndicators {
+			ura += a.tiles.ToCount().Get(doraFromIndicator(ind))
+		}
+		if ura > 0 {
+			r.add("Ura dora", ura)
+		}
+	}
+	if w.RedFives > 0 {
+		r.add("Red fives", w.RedFives)
+	}
+
+	r.Han = r.Total
+	r.Fu = riichiFu(w, a, closed, pinfu)
+	r.Base = r.Fu * (1 << (2 + r.Han))
+
+	switch {
+	case r.Han >= 13:
+		r.LimitName, r.Base, r.Yakuman = "kazoe yakuman", 8000, 1
+	case r.Han >= 11:
+		r.LimitName, r.Base = "sanbaiman", 6000
+	case r.Han >= 8:
+		r.LimitName, r.Base = "baiman", 4000
+	case r.Han >= 6:
+		r.LimitName, r.Base = "haneman", 3000
+	case r.Han >= 5 || r.Base >= 2000:
+		r.LimitName, r.Base = "mangan", 2000
+	}
+	r.Limit = r.LimitName != ""
+	r.pay(w)
+	return r, true
+}
+
+// pay fills in the payments from the base points.
+func (r *RiichiScore) pay(w Win) {
+	dealer := w.Seat == mj.East
+	switch {
+	case !w.SelfDrawn && dealer:
+		r.Ron = roundUp100(6 * r.Base)
+	case !w.SelfDrawn:
+		r.Ron = roundUp100(4 * r.Base)
+	case dealer:
+		r.NonDealer = roundUp100(2 * r.Base)
+	default:
+		r.Dealer = roundUp100(2 * r.Base)
+		r.NonDealer = roundUp100(r.Base)
+	}
+}
+
+func roundUp100(n int) int {
+	return (n + 99) / 100 * 100
+}
+
+// doraFromIndicator returns the tile after the indicator: the next number in the same
+// suit, the next wind in seating order, or the next dragon in the order white, green, red.
+func doraFromIndicator(t mj.Tile) mj.Tile {
+	switch {
+	case t.IsBasic():
+		t.Value = t.Value%9 + 1
+	case isWind(t):
+		t.Value = mj.East + (t.Value-mj.East+1)%4
+	case t.Value == mj.Ban:
+		t.Value = mj.Fa
+	case t.Value == mj.Fa:
+		t.Value = mj.Zhong
+	case t.Value == mj.Zhong:
+		t.Value = mj.Ban
+	}
+	return t
+}
+
+type yaku struct {
+	name string
+	han  int
+}
+
+// riichiYakuman returns the names of the yakuman in the hand.
+func riichiYakuman(w Win, a arrangement, closed bool) []string {
+	var ys []string
+	allIn := func(f func(t mj.Tile) bool) bool {
+		for _, t := range a.tiles {
+			if !f(t) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if w.FirstTurn && w.SelfDrawn && closed {
+		if w.Seat == mj.East {
+			ys = append(ys, "Tenhou")
+		} else {
+			ys = append(ys, "Chiihou")
+		}
+	}
+	if a.special == "thirteen orphans" {
+		return append(ys, "Kokushi musou")
+	}
+
+	if allIn(isHonour) {
+		ys = append(ys, "Tsuuiisou")
+	}
+	if allIn(mj.Tile.IsTerminal) {
+		ys = append(ys, "Chinroutou")
+	}
+	green := mj.MustParseHand("b2 b3 b4 b6 b8 hf").ToCount()
+	if allIn(func(t mj.Tile) bool { return green.Get(t) > 0 }) {
+		ys = append(ys, "Ryuuiisou")
+	}
+	if a.special != "" {
+		return ys
+	}
+
+	if closed && len(a.tiles) == 14 {
+		if ss, honours := suits(a.tiles); len(ss) == 1 && !honours && isNineGates(w, a) {
+			ys = append(ys, "Chuuren poutou")
+		}
+	}
+
+	concealed, dragons, winds, kongs := 0, 0, 0, 0
+	for _, m := range a.sets {
+		if m.Kind == mj.Chi {
+			continue
+		}
+		if m.Concealed {
+			concealed++
+		}
+		if m.Kind == mj.Gang {
+			kongs++
+		}
+		if isDragon(m.Tile) {
+			dragons++
+		}
+		if isWind(m.Tile) {
+			winds++
+		}
+	}
+	if concealed == 4 {
+		ys = append(ys, "Suuankou")
+	}
+	if dragons == 3 {
+		ys = append(ys, "Daisangen")
+	}
+	if winds == 4 {
+		ys = append(ys, "Daisuushii")
+	} else if winds == 3 && isWind(a.pair) {
+		ys = append(ys, "Shousuushii")
+	}
+	if kongs == 4 {
+		ys = append(ys, "Suukantsu")
+	}
+	return ys
+}
+
+// riichiYaku returns the ordinary yaku in the hand, with their han.
+func riichiYaku(w Win, a arrangement, closed bool) []yaku {
+	var ys []yaku
+	// closedHan returns the han for a yaku that is worth one less when open
+	closedHan := func(han int) int {
+		if closed {
+			return han
+		}
+		return han - 1
+	}
+
+	switch {
+	case w.DoubleRiichi:
+		ys = append(ys, yaku{"Double riichi", 2})
+	case w.Riichi:
+		ys = append(ys, yaku{"Riichi", 1})
+	}
+	if w.Ippatsu && (w.Riichi || w.DoubleRiichi) {
+		ys = append(ys, yaku{"Ippatsu", 1})
+	}
+	if closed && w.SelfDrawn {
+		ys = append(ys, yaku{"Menzen tsumo", 1})
+	}
+	switch {
+	case w.LastWallTile && w.SelfDrawn:
+		ys = append(ys, yaku{"Haitei raoyue", 1})
+	case w.LastWallTile:
+		ys = append(ys, yaku{"Houtei raoyui", 1})
+	}
+	if w.AfterGang {
+		ys = append(ys, yaku{"Rinshan kaihou", 1})
+	}
+	if w.RobbedGang {
+		ys = append(ys, yaku{"Chankan", 1})
+	}
+
+	simples, orphans, honours := true, true, false
+	for _, t := range a.tiles {
+		simples = simples && t.IsBasic() && !t.IsTerminal()
+		orphans = orphans && isOrphan(t)
+		honours = honours || isHonour(t)
+	}
+	if simples {
+		ys = append(ys, yaku{"Tanyao", 1})
+	}
+	if orphans {
+		ys = append(ys, yaku{"Honroutou", 2})
+	}
+	ss, _ := suits(a.tiles)
+	switch {
+	case len(ss) == 1 && !honours:
+		ys = append(ys, yaku{"Chinitsu", closedHan(6)})
+	case len(ss) == 1:
+		ys = append(ys, yaku{"Honitsu", closedHan(3)})
+	}
+
+	if a.special == "seven pairs" {
+		return append(ys, yaku{"Chiitoitsu", 2})
+	}
+
+	var chis mj.Hand
+	var pungs []mj.Meld
+	concealedPungs, kongs, dragons := 0, 0, 0
+	for _, m := range a.sets {
+		if m.Kind == mj.Chi {
+			chis = append(chis, m.Tile)
+			continue
+		}
+		pungs = append(pungs, m)
+		if m.Concealed {
+			concealedPungs++
+		}
+		if m.Kind == mj.Gang {
+			kongs++
+		}
+		switch {
+		case isDragon(m.Tile):
+			dragons++
+			ys = append(ys, yaku{"Yakuhai", 1})
+		case isWind(m.Tile):
+			if m.Tile.Value == w.Seat {
+				ys = append(ys, yaku{"Yakuhai (seat wind)", 1})
+			}
+			if m.Tile.Value == w.Round {
+				ys = append(ys, yaku{"Yakuhai (round wind)", 1})
+			}
+		}
+	}
+
+	if closed && len(chis) == 4 && a.wait == wait.TwoSided && !w.valuedPair(a.pair) {
+		ys = append(ys, yaku{"Pinfu", 1})
+	}
+	if closed {
+		switch identicalChiPairs(chis) {
+		case 2:
+			ys = append(ys, yaku{"Ryanpeikou", 3})
+		case 1:
+			ys = append(ys, yaku{"Iipeikou", 1})
+		}
+	}
+
+	if len(pungs) == 4 {
+		ys = append(ys, yaku{"Toitoi", 2})
+	}
+	if concealedPungs == 3 {
+		ys = append(ys, yaku{"Sanankou", 2})
+	}
+	if kongs == 3 {
+		ys = append(ys, yaku{"Sankantsu", 2})
+	}
+	if dragons == 2 && isDragon(a.pair) {
+		ys = append(ys, yaku{"Shousangen", 2})
+	}
+
+	sameValue := func(ts mj.Hand) bool {
+		found := false
+		forCombos(len(ts), 3, func(idx []int) bool {
+			x, y, z := ts[idx[0]], ts[idx[1]], ts[idx[2]]
+			found = differentSuits(x, y, z) && x.Value == y.Value && y.Value == z.Value
+			return !found
+		})
+		return found
+	}
+	if sameValue(chis) {
+		ys = append(ys, yaku{"Sanshoku doujun", closedHan(2)})
+	}
+	var pungTiles mj.Hand
+	for _, m := range pungs {
+		if m.Tile.IsBasic() {
+			pungTiles = append(pungTiles, m.Tile)
+		}
+	}
+	if sameValue(pungTiles) {
+		ys = append(ys, yaku{"Sanshoku doukou", 2})
+	}
+
+	ittsu := false
+	forCombos(len(chis), 3, func(idx []int) bool {
+		x, y, z := chis[idx[0]], chis[idx[1]], chis[idx[2]]
+		ittsu = sameSuit(x, y, z) && x.Value == 1 && y.Value == 4 && z.Value == 7
+		return !ittsu
+	})
+	if ittsu {
+		ys = append(ys, yaku{"Ittsu", closedHan(2)})
+	}
+
+	// every set and the pair contains a terminal or honour, with at least one chi
+	outside := isOrphan(a.pair) && len(chis) > 0
+	for _, m := range a.sets {
+		if m.Kind == mj.Chi {
+			outside = outside && (m.Tile.Value == 1 || m.Tile.Value == 7)
+		} else {
+			outside = outside && isOrphan(m.Tile)
+		}
+	}
+	switch {
+	case outside && !honours:
+		ys = append(ys, yaku{"Junchan", closedHan(3)})
+	case outside:
+		ys = append(ys, yaku{"Chanta", closedHan(2)})
+	}
+
+	return ys
+}
+
+// identicalChiPairs counts the pairs of identical chis, each chi being used at most once.
+func identicalChiPairs(chis mj.Hand) int {
+	n := 0
+	chis.ToCount().ForEach(func(t mj.Tile, c int) bool {
+		n += c / 2
+		return true
+	})
+	return n
+}
+
+// riichiFu calculates the fu of a hand, rounded up to the nearest 10 except for seven pairs.
+func riichiFu(w Win, a arrangement, closed, pinfu bool) int {
+	switch {
+	case a.special == "seven pairs":
+		return 25
+	case pinfu && w.SelfDrawn:
+		return 20
+	case pinfu:
+		return 30
+	}
+
+	fu := 20
+	if closed && !w.SelfDrawn {
+		fu += 10
+	}
+	if w.SelfDrawn {
+		fu += 2
+	}
+
+	if isDragon(a.pair) {
+		fu += 2
+	}
+	if isWind(a.pair) && a.pair.Value == w.Seat {
+		fu += 2
+	}
+	if isWind(a.pair) && a.pair.Value == w.Round {
+		fu += 2
+	}
+
+	switch a.wait {
+	case wait.Edge, wait.Closed, wait.Single:
+		fu += 2
+	}
+
+	for _, m := range a.sets {
+		if m.Kind == mj.Chi {
+			continue
+		}
+		f := 2
+		if isOrphan(m.Tile) {
+			f *= 2
+		}
+		if m.Concealed {
+			f *= 2
+		}
+		if m.Kind == mj.Gang {
+			f *= 4
+		}
+		fu += f
+	}
+
+	if fu == 20 {
+		// an open hand with no fu is rounded up to 30
+		return 30
+	}
+	return (fu + 9) / 10 * 10
+}
@@ -0,0 +1,249 @@
+package score
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestRiichiScorer_Points(t *testing.T) {
+	noSevenPairs := mj.Riichi
+	noSevenPairs.Specials = mj.ThirteenOrphansHand
+	repeatPairs := mj.Riichi
+	repeatPairs.SevenPairsRepeat = true
+
+	tests := []struct {
+		name       string
+		win        Win
+		wantHan    int
+		wantFu     int
+		wantPoints int
+		wantErr    error
+	}{
+		{
+			"riichi pinfu tsumo",
+			Win{
+				Concealed:   mj.MustParseHand("b2 b3 b4 c3 c4 c5 w6 w7 w8 b6 b7 b8 c9 c9"),
+				WinningTile: mustParseTile("b8"),
+				SelfDrawn:   true,
+				Riichi:      true,
+				Seat:        mj.South,
+				Round:       mj.East,
+			},
+			3,
+			20,
+			// 700 from each non-dealer and 1300 from the dealer
+			2700,
+			nil,
+		},
+		{
+			"open tanyao",
+			Win{
+				Concealed:   mj.MustParseHand("c3 c4 c5 w6 w7 w8 b6 b7 c6 c6 b8"),
+				Melds:       []mj.Meld{{Kind: mj.Chi, Tile: mustParseTile("b2")}},
+				WinningTile: mustParseTile("b8"),
+				Seat:        mj.West,
+				Round:       mj.East,
+			},
+			1,
+			30,
+			1000,
+			nil,
+		},
+		{
+			"dora and red fives",
+			Win{
+				Concealed:      mj.MustParseHand("c3 c4 c5 w6 w7 w8 b6 b7 c6 c6 b8"),
+				Melds:          []mj.Meld{{Kind: mj.Chi, Tile: mustParseTile("b2")}},
+				WinningTile:    mustParseTile("b8"),
+				DoraIndicators: mj.MustParseHand("c5"),
+				UraIndicators:  mj.MustParseHand("b1"),
+				RedFives:       1,
+				Seat:           mj.East,
+				Round:          mj.East,
+			},
+			// tanyao, 2 dora, 1 red five, no ura without riichi
+			4,
+			30,
+			11600,
+			nil,
+		},
+		{
+			"ryanpeikou beats chiitoitsu",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b1 b2 b2 b3 b3 c4 c4 c5 c5 c6 c6 w9 w9"),
+				WinningTile: mustParseTile("w9"),
+				Seat:        mj.North,
+				Round:       mj.East,
+			},
+			3,
+			40,
+			5200,
+			nil,
+		},
+		{
+			"chiitoitsu",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b1 b5 b5 b8 b8 c4 c4 c7 c7 hz hz w9 w9"),
+				WinningTile: mustParseTile("w9"),
+				Riichi:      true,
+				Seat:        mj.North,
+				Round:       mj.East,
+			},
+			3,
+			25,
+			3200,
+			nil,
+		},
+		{
+			"daisangen",
+			Win{
+				Concealed:   mj.MustParseHand("hz hz hz hf hf hf b2 b3 b4 c5 c5"),
+				Melds:       []mj.Meld{{Kind: mj.Peng, Tile: mustParseTile("hb")}},
+				WinningTile: mustParseTile("b4"),
+				Seat:        mj.North,
+				Round:       mj.East,
+			},
+			13,
+			0,
+			32000,
+			nil,
+		},
+		{
+			"kokushi dealer tsumo",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b9 c1 c9 w1 w9 he hs hw hn hz hf hb hb"),
+				WinningTile: mustParseTile("hb"),
+				SelfDrawn:   true,
+				Seat:        mj.East,
+				Round:       mj.East,
+			},
+			13,
+			0,
+			48000,
+			nil,
+		},
+		{
+			"sanma",
+			Win{
+				Concealed:   mj.MustParseHand("b2 b2 b2 c3 c3 c3 w9 w9 w9 hz hz hz c6 c6"),
+				WinningTile: mustParseTile("w9"),
+				Seat:        mj.South,
+				Round:       mj.East,
+				Rules:       mj.Sanma,
+			},
+			// toitoi 2, sanankou 2, chun 1
+			5,
+			50,
+			8000,
+			nil,
+		},
+		{
+			"sanma chi",
+			Win{
+				Concealed:   mj.MustParseHand("c3 c4 c5 b6 b7 b8 b6 b7 c6 c6 b8"),
+				Melds:       []mj.Meld{{Kind: mj.Chi, Tile: mustParseTile("b2")}},
+				WinningTile: mustParseTile("b8"),
+				Seat:        mj.West,
+				Round:       mj.East,
+				Rules:       mj.Sanma,
+			},
+			0,
+			0,
+			0,
+			ErrInvalidHand,
+		},
+		{
+			"seven pairs",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b1 c3 c3 c7 c7 w2 w2 w5 w5 hn hn hz hz"),
+				WinningTile: mustParseTile("hz"),
+				Seat:        mj.West,
+				Round:       mj.East,
+			},
+			2,
+			25,
+			1600,
+			nil,
+		},
+		{
+			"seven pairs not allowed",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b1 c3 c3 c7 c7 w2 w2 w5 w5 hn hn hz hz"),
+				WinningTile: mustParseTile("hz"),
+				Seat:        mj.West,
+				Round:       mj.East,
+				Rules:       noSevenPairs,
+			},
+			0,
+			0,
+			0,
+			ErrNotWinning,
+		},
+		{
+			"seven pairs with four identical tiles",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b1 b1 b1 c7 c7 w2 w2 w5 w5 hn hn hz hz"),
+				WinningTile: mustParseTile("hz"),
+				Seat:        mj.West,
+				Round:       mj.East,
+			},
+			0,
+			0,
+			0,
+			ErrNotWinning,
+		},
+		{
+			"seven pairs with four identical tiles allowed",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b1 b1 b1 c7 c7 w2 w2 w5 w5 hn hn hz hz"),
+				WinningTile: mustParseTile("hz"),
+				Seat:        mj.West,
+				Round:       mj.East,
+				Rules:       repeatPairs,
+			},
+			2,
+			25,
+			1600,
+			nil,
+		},
+		{
+			"no yaku",
+			Win{
+				Concealed:   mj.MustParseHand("c3 c4 c5 w6 w7 w8 b6 b7 c6 c6 b8"),
+				Melds:       []mj.Meld{{Kind: mj.Chi, Tile: mustParseTile("b1")}},
+				WinningTile: mustParseTile("b8"),
+				Seat:        mj.West,
+				Round:       mj.East,
+			},
+			0,
+			0,
+			0,
+			ErrNotWinning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RiichiScorer{}.Points(tt.win)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Points() err = %v, want %v", err, tt.wantErr)
+			}
+			if got.Han != tt.wantHan || got.Fu != tt.wantFu || got.Points() != tt.wantPoints {
+				t.Errorf("Points() = %d han %d fu %d points, want %d han %d fu %d points\n%v",
+					got.Han, got.Fu, got.Points(), tt.wantHan, tt.wantFu, tt.wantPoints, got.Breakdown)
+			}
+		})
+	}
+}
+
+func Test_doraFromIndicator(t *testing.T) {
+	for ind, want := range map[string]string{
+		"b1": "b2", "b9": "b1", "hn": "he", "he": "hs", "hb": "hf", "hf": "hz", "hz": "hb",
+	} {
+		if got := doraFromIndicator(mustParseTile(ind)); got != mustParseTile(want) {
+			t.Errorf("doraFromIndicator(%s) = %v, want %s", ind, got, want)
+		}
+	}
+}
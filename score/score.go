@@ -0,0 +1,343 @@
+package score
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+	"github.com/nik0sc/mj/special"
+	"github.com/nik0sc/mj/wait"
+)
+
+var (
+	ErrNotWinning     = errors.New("not a winning hand")
+	ErrInvalidHand    = errors.New("invalid hand")
+	ErrUnknownScoring = errors.New("unknown scoring table")
+)
+
+// Win describes a winning hand and the circumstances of the win.
+type Win struct {
+	// Concealed holds the tiles that are not part of a declared Meld,
+	// including the winning tile.
+	Concealed mj.Hand
+	// Melds holds the declared melds, including concealed gangs.
+	Melds []mj.Meld
+	// Bonus holds the flowers, seasons and animals drawn by the winner.
+	Bonus mj.Hand
+	// WinningTile is the tile that completed the hand. It must also be in Concealed.
+	WinningTile mj.Tile
+	// SelfDrawn is true if the winning tile was drawn from the wall.
+	SelfDrawn bool
+	// Seat is the seat wind of the winner, one of mj.East, mj.South, mj.West or mj.North.
+	Seat mj.Value
+	// Round is the prevailing wind.
+	Round mj.Value
+	// Rules is the ruleset the hand was played under. It decides which tiles are in play,
+	// whether chis are allowed and which special hands can win. Unlike elsewhere, the
+	// zero Ruleset is not the basic Hong Kong ruleset: it means that no Ruleset was
+	// given, and each Scorer uses the preset for its own scoring table, such as mj.MCR.
+	Rules mj.Ruleset
+
+	// LastTile is true if the winning tile was the last copy of that tile that was not
+	// already visible.
+	LastTile bool
+	// LastWallTile is true if the hand was won on the last tile of the wall, or on the
+	// discard following it.
+	LastWallTile bool
+	// AfterGang is true if the winning tile was the replacement drawn after declaring a gang.
+	AfterGang bool
+	// RobbedGang is true if the winning tile was added by another player to a peng to
+	// make a gang.
+	RobbedGang bool
+	// FirstTurn is true if the hand was won on the player's first draw, with no calls made
+	// beforehand.
+	FirstTurn bool
+	// DealerStreak is the number of consecutive hands the dealer has won or drawn before
+	// this one. It is only used in Taiwanese scoring.
+	DealerStreak int
+
+	// The following are only used in riichi.
+
+	// Riichi is true if the winner declared riichi, and DoubleRiichi is true if that was
+	// done on the first turn.
+	Riichi, DoubleRiichi bool
+	// Ippatsu is true if the hand was won within one go-around of declaring riichi.
+	Ippatsu bool
+	// DoraIndicators and UraIndicators are the indicator tiles, not the dora themselves.
+	// The ura indicators are ignored unless riichi was declared.
+	DoraIndicators, UraIndicators mj.Hand
+	// RedFives is the number of red fives in the hand. A Tile cannot tell a red five from
+	// the others, so they are counted here.
+	RedFives int
+}
+
+// Item is one scoring element of a winning hand.
+type Item struct {
+	Name   string
+	Points int
+}
+
+// Breakdown is the full scoring of a winning hand.
+type Breakdown struct {
+	Items []Item
+	// Total is the sum of the Points of each Item, after applying the limit.
+	Total int
+	// Limit is true if the hand reached the maximum score.
+	Limit bool
+}
+
+func (b *Breakdown) add(name string, points int) {
+	b.Items = append(b.Items, Item{name, points})
+	b.Total += points
+}
+
+// String returns a human-readable breakdown, one item per line.
+func (b Breakdown) String() string {
+	var s string
+	for _, it := range b.Items {
+		s += fmt.Sprintf("%s: %d\n", it.Name, it.Points)
+	}
+	s += fmt.Sprintf("total: %d", b.Total)
+	if b.Limit {
+		s += " (limit)"
+	}
+	return s
+}
+
+// Scorer scores winning hands according to some ruleset.
+type Scorer interface {
+	Score(w Win) (Breakdown, error)
+}
+
+// For returns the Scorer named by the Ruleset's Scoring field, which is one of
+// "singapore", "mcr", "riichi" or "taiwanese". The Scorer is ready to use with its
+// default options.
+func For(rs mj.Ruleset) (Scorer, error) {
+	switch rs.Scoring {
+	case "singapore":
+		return SingaporeScorer{}, nil
+	case "mcr":
+		return MCRScorer{}, nil
+	case "riichi":
+		return RiichiScorer{}, nil
+	case "taiwanese":
+		return TaiwaneseScorer{}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownScoring, rs.Scoring)
+}
+
+// arrangement is one interpretation of a winning hand. Either sets and pair are
+// filled in, or special names a hand that does not follow the usual structure.
+type arrangement struct {
+	// All sets in the hand. Sets formed in the concealed part of the hand are
+	// marked concealed, except for a peng completed by a discard.
+	sets []mj.Meld
+	pair mj.Tile
+	// Index into sets of the set completed by the winning tile, or -1 for the pair.
+	win  int
+	wait wait.Shape
+
+	special string
+	// All the tiles in the hand, including melds.
+	tiles mj.Hand
+}
+
+// withRules returns the Win with Rules set to rs if no Ruleset was given.
+func (w Win) withRules(rs mj.Ruleset) Win {
+	if w.Rules.IsZero() {
+		w.Rules = rs
+	}
+	return w
+}
+
+func (w Win) validate(handSize int) error {
+	if !w.Concealed.Valid() || !w.Bonus.Valid() {
+		return ErrInvalidHand
+	}
+	n := len(w.Concealed)
+	for _, m := range w.Melds {
+		if !m.Valid() {
+			return ErrInvalidHand
+		}
+		if m.Kind == mj.Chi && w.Rules.NoChi {
+			return fmt.Errorf("%w: chi is not allowed", ErrInvalidHand)
+		}
+		n += 3
+	}
+	if err := w.Rules.Tiles.CheckHand(w.allTiles()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidHand, err)
+	}
+	if n != handSize+1 {
+		return fmt.Errorf("%w: %d tiles, want %d", ErrInvalidHand, n, handSize+1)
+	}
+	for _, t := range w.Bonus {
+		if !t.IsBonus() {
+			return fmt.Errorf("%w: %s is not a bonus tile", ErrInvalidHand, t)
+		}
+	}
+	for _, t := range w.Concealed {
+		if t == w.WinningTile {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: winning tile %s not in hand", ErrInvalidHand, w.WinningTile)
+}
+
+// allTiles returns the concealed tiles and the tiles of every meld, sorted.
+func (w Win) allTiles() mj.Hand {
+	h := make(mj.Hand, len(w.Concealed))
+	copy(h, w.Concealed)
+	for _, m := range w.Melds {
+		h = append(h, m.Tiles()...)
+	}
+	sort.Sort(h)
+	return h
+}
+
+// arrangements finds every interpretation of the winning hand, including the position
+// of the winning tile. Thirteen orphans and seven pairs are included when the hand
+// has no melds and the Ruleset allows them. In 16-tile hands, seven pairs is the ligu
+// ligu hand of seven pairs and a set of three identical tiles.
+func (w Win) arrangements() []arrangement {
+	var out []arrangement
+	tiles := w.allTiles()
+
+	for _, g := range handcheck.CompleteWithRules(w.Concealed, w.Rules) {
+		var sets []mj.Meld
+		sets = append(sets, w.Melds...)
+		concealedFrom := len(sets)
+		for _, t := range g.Pengs {
+			sets = append(sets, mj.Meld{Kind: mj.Peng, Tile: t, Concealed: true})
+		}
+		for _, t := range g.Chis {
+			sets = append(sets, mj.Meld{Kind: mj.Chi, Tile: t, Concealed: true})
+		}
+
+		if g.Pairs[0] == w.WinningTile {
+			out = append(out, arrangement{
+				sets: sets, pair: g.Pairs[0], win: -1, wait: wait.Single, tiles: tiles,
+			})
+		}
+
+		seen := make(map[mj.Meld]bool)
+		for i := concealedFrom; i < len(sets); i++ {
+			m := sets[i]
+			if !m.Contains(w.WinningTile) || seen[m] {
+				continue
+			}
+			seen[m] = true
+
+			a := arrangement{pair: g.Pairs[0], win: i, tiles: tiles}
+			a.sets = make([]mj.Meld, len(sets))
+			copy(a.sets, sets)
+
+			if m.Kind == mj.Peng {
+				a.wait = wait.DualPung
+				// a peng completed by a discard counts as exposed
+				a.sets[i].Concealed = w.SelfDrawn
+			} else {
+				a.wait = wait.ChiShape(m.Tile, w.WinningTile)
+			}
+			out = append(out, a)
+		}
+	}
+
+	if len(w.Melds) == 0 {
+		h := w.Concealed.Remove(indexOf(w.Concealed, w.WinningTile))
+		// the zero wait means any of the thirteen tiles
+		if ok, waiting := special.IsThirteenOrphansWithRules(h, w.Rules); ok &&
+			(waiting == w.WinningTile || !waiting.Valid() && isOrphan(w.WinningTile)) {
+			out = append(out, arrangement{special: "thirteen orphans", win: -1, tiles: tiles})
+		}
+		if ok, waits := special.IsSevenPairsWithRules(h, w.Rules); ok && indexOf(waits, w.WinningTile) >= 0 {
+			if w.Rules.Size() == 16 {
+				out = append(out, arrangement{special: "ligu ligu", win: -1, tiles: tiles})
+			} else {
+				out = append(out, arrangement{special: "seven pairs", win: -1, wait: wait.Single, tiles: tiles})
+			}
+		}
+	}
+
+	return out
+}
+
+func indexOf(h mj.Hand, t mj.Tile) int {
+	for i, t2 := range h {
+		if t == t2 {
+			return i
+		}
+	}
+	return -1
+}
+
+func isOrphan(t mj.Tile) bool {
+	return t.IsTerminal() || t.Suit == mj.Honour
+}
+
+func isDragon(t mj.Tile) bool {
+	return t.Suit == mj.Honour && t.Value >= mj.Zhong
+}
+
+func isWind(t mj.Tile) bool {
+	return t.Suit == mj.Honour && t.Value <= mj.North
+}
+
+// suits returns the set of basic suits used, and whether honours are used.
+func suits(h mj.Hand) (map[mj.Suit]bool, bool) {
+	ss := make(map[mj.Suit]bool)
+	honours := false
+	for _, t := range h {
+		if t.Suit == mj.Honour {
+			honours = true
+		} else if t.IsBasic() {
+			ss[t.Suit] = true
+		}
+	}
+	return ss, honours
+}
+
+// valuedPair returns true if a pung of the tile would score: dragons, and the seat
+// and prevailing winds.
+func (w Win) valuedPair(t mj.Tile) bool {
+	return isDragon(t) || isWind(t) && (t.Value == w.Seat || t.Value == w.Round)
+}
+
+// seatIndex converts a wind into a seat number between 0 and 3.
+func seatIndex(wind mj.Value) int {
+	return int(wind - mj.East)
+}
+
+// count returns the number of sets of the given kind, counting gangs as pengs.
+func (a arrangement) count(kind mj.MeldKind) int {
+	n := 0
+	for _, m := range a.sets {
+		if m.Kind == kind || (kind == mj.Peng && m.Kind == mj.Gang) {
+			n++
+		}
+	}
+	return n
+}
+
+// best returns the highest scoring breakdown of all the arrangements. The scoring
+// function should return false for arrangements that are not winning hands under
+// its rules.
+func best(as []arrangement, f func(a arrangement) (Breakdown, bool)) (Breakdown, error) {
+	var b Breakdown
+	found := false
+	for _, a := range as {
+		bNew, ok := f(a)
+		if !ok {
+			continue
+		}
+		if !found || bNew.Limit && !b.Limit || bNew.Total > b.Total && bNew.Limit == b.Limit {
+			b = bNew
+			found = true
+		}
+	}
+	if !found {
+		return Breakdown{}, ErrNotWinning
+	}
+	return b, nil
+}
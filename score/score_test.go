@@ -0,0 +1,23 @@
+package score
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestFor(t *testing.T) {
+	for _, rs := range mj.Presets() {
+		s, err := For(rs)
+		if rs.Scoring == "" {
+			if !errors.Is(err, ErrUnknownScoring) {
+				t.Errorf("For(%s) err = %v, want %v", rs.Name, err, ErrUnknownScoring)
+			}
+			continue
+		}
+		if err != nil || s == nil {
+			t.Errorf("For(%s) = %v, %v", rs.Name, s, err)
+		}
+	}
+}
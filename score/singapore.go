@@ -0,0 +1,229 @@
+package score
+
+import (
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/wait"
+)
+
+// SingaporeScorer scores hands in tai according to common Singapore rules.
+// The zero value is ready to use with a limit of 5 tai.
+//
+// The following elements are scored:
+//  - 1 tai for each flower or season matching the winner's seat
+//  - 1 tai for each animal, and another 1 tai for each pair of cat and mouse or
+//    rooster and centipede
+//  - 1 tai for no bonus tiles at all
+//  - 1 tai for each dragon pung, and for a pung of the seat or prevailing wind
+//  - 1 tai for pinghu: all chis, a pair that would not score as a pung, and a two-sided wait
+//  - 2 tai for all pungs
+//  - 2 tai for half colour (one suit with honours) or 4 tai for full colour (one suit only)
+//  - 2 tai for small three dragons (in addition to the two dragon pungs)
+//  - the limit for thirteen orphans, big three dragons, great four winds and all honours
+type SingaporeScorer struct {
+	// MaxTai is the maximum tai for a hand. Zero means 5.
+	MaxTai int
+}
+
+// Score finds the highest scoring interpretation of a winning hand.
+func (s SingaporeScorer) Score(w Win) (Breakdown, error) {
+	w = w.withRules(mj.Singapore)
+	if err := w.validate(w.Rules.Size()); err != nil {
+		return Breakdown{}, err
+	}
+
+	maxTai := s.MaxTai
+	if maxTai == 0 {
+		maxTai = 5
+	}
+
+	b, err := best(w.arrangements(), func(a arrangement) (Breakdown, bool) {
+		return s.score(w, a)
+	})
+	if err != nil {
+		return Breakdown{}, err
+	}
+
+	if b.Limit || b.Total >= maxTai {
+		b.Total = maxTai
+		b.Limit = true
+	}
+	return b, nil
+}
+
+func (SingaporeScorer) score(w Win, a arrangement) (Breakdown, bool) {
+	var b Breakdown
+
+	singaporeBonus(&b, w)
+
+	switch a.special {
+	case "":
+	case "thirteen orphans":
+		b.add("Thirteen orphans", 0)
+		b.Limit = true
+		return b, true
+	default:
+		// the table has no score for seven pairs, which mj.Singapore does not allow
+		return Breakdown{}, false
+	}
+
+	dragons, winds := 0, 0
+	for _, m := range a.sets {
+		if m.Kind == mj.Chi {
+			continue
+		}
+		if isDragon(m.Tile) {
+			dragons++
+			b.add("Dragon pung", 1)
+		}
+		if isWind(m.Tile) {
+			winds++
+			if m.Tile.Value == w.Seat {
+				b.add("Seat wind", 1)
+			}
+			if m.Tile.Value == w.Round {
+				b.add("Prevailing wind", 1)
+			}
+		}
+	}
+
+	if dragons == 3 {
+		b.add("Big three dragons", 0)
+		b.Limit = true
+	} else if dragons == 2 && isDragon(a.pair) {
+		b.add("Small three dragons", 2)
+	}
+	if winds == 4 {
+		b.add("Great four winds", 0)
+		b.Limit = true
+	}
+
+	ss, honours := suits(a.tiles)
+	if len(ss) == 0 {
+		b.add("All honours", 0)
+		b.Limit = true
+	} else if len(ss) == 1 && honours {
+		b.add("Half colour", 2)
+	} else if len(ss) == 1 {
+		b.add("Full colour", 4)
+	}
+
+	if a.count(mj.Peng) == 4 {
+		b.add("All pungs", 2)
+	} else if a.count(mj.Chi) == 4 && a.wait == wait.TwoSided && !w.valuedPair(a.pair) {
+		b.add("Pinghu", 1)
+	}
+
+	return b, true
+}
+
+// singaporeBonus adds the tai for flowers, seasons and animals.
+func singaporeBonus(b *Breakdown, w Win) {
+	if len(w.Bonus) == 0 {
+		b.add("No bonus tiles", 1)
+		return
+	}
+
+	seat := seatIndex(w.Seat)
+	had := make(map[mj.Value]bool)
+	for _, t := range w.Bonus {
+		had[t.Value] = true
+		if t.IsAnimal() {
+			b.add("Animal", 1)
+		} else if int(t.Value-mj.FlowerBase)%4 == seat {
+			b.add("Seat flower", 1)
+		}
+	}
+
+	if had[mj.Cat] && had[mj.Mouse] {
+		b.add("Cat and mouse", 1)
+	}
+	if had[mj.Rooster] && had[mj.Centipede] {
+		b.add("Rooster and centipede", 1)
+	}
+}
+
+// SingaporePayout returns the number of units paid by the discarder and by each of the
+// other losing players for a hand worth tai. A hand is worth 1 unit at 0 tai and doubles
+// with each tai. A self-drawn win is paid double by everyone. Otherwise, the discarder
+// pays double and the others pay single, unless shooter is true, in which case the
+// discarder pays for everyone.
+func SingaporePayout(tai int, selfDrawn, shooter bool) (discarder, others int) {
+	v := 1 << tai
+	switch {
+	case selfDrawn:
+		return 2 * v, 2 * v
+	case shooter:
+		return 4 * v, 0
+	}
+	return 2 * v, v
+}
+
+// Bonus is a payment made immediately when a bonus tile is drawn, before the hand is won.
+type Bonus struct {
+	Name string
+	// Units paid by each other player.
+	Units int
+}
+
+// SingaporeBonus returns the immediate payments earned by drawing a bonus tile,
+// given the bonus tiles the player already has. Completing a pair of animals that hunt
+// each other (cat and mouse, rooster and centipede) earns 1 unit from each player, and
+// completing all four animals earns another 2 units. Completing all four flowers or all
+// four seasons earns 2 units.
+func SingaporeBonus(have mj.Hand, drawn mj.Tile) []Bonus {
+	if !drawn.IsBonus() {
+		return nil
+	}
+
+	had := make(map[mj.Value]bool)
+	for _, t := range have {
+		had[t.Value] = true
+	}
+	if had[drawn.Value] {
+		return nil
+	}
+
+	var out []Bonus
+	if drawn.IsAnimal() {
+		partner := map[mj.Value]mj.Value{
+			mj.Cat:       mj.Mouse,
+			mj.Mouse:     mj.Cat,
+			mj.Rooster:   mj.Centipede,
+			mj.Centipede: mj.Rooster,
+		}[drawn.Value]
+
+		if had[partner] {
+			if drawn.Value == mj.Cat || drawn.Value == mj.Mouse {
+				out = append(out, Bonus{"Cat and mouse", 1})
+			} else {
+				out = append(out, Bonus{"Rooster and centipede", 1})
+			}
+		}
+		if countTrue(had, mj.Rooster, mj.Mouse) == 3 {
+			out = append(out, Bonus{"All animals", 2})
+		}
+		return out
+	}
+
+	// flowers are 1-4, seasons are 5-8
+	base := mj.FlowerBase
+	name := "All flowers"
+	if drawn.Value >= mj.FlowerBase+4 {
+		base += 4
+		name = "All seasons"
+	}
+	if countTrue(had, base, base+3) == 3 {
+		out = append(out, Bonus{name, 2})
+	}
+	return out
+}
+
+func countTrue(m map[mj.Value]bool, from, to mj.Value) int {
+	n := 0
+	for v := from; v <= to; v++ {
+		if m[v] {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,169 @@
+package score
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func mustParseTile(s string) mj.Tile {
+	return mj.MustParseHand(s)[0]
+}
+
+func TestSingaporeScorer_Score(t *testing.T) {
+	tests := []struct {
+		name      string
+		win       Win
+		wantTotal int
+		wantLimit bool
+		wantErr   error
+	}{
+		{
+			"pinghu",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b2 b3 b4 b5 b6 c2 c3 c4 w6 w7 w8 c9 c9"),
+				WinningTile: mustParseTile("b4"),
+				Seat:        mj.East,
+				Round:       mj.East,
+			},
+			// no bonus 1 + pinghu 1
+			2,
+			false,
+			nil,
+		},
+		{
+			"hand size from the rules",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b2 b3 b4 b5 b6 c2 c3 c4 w6 w7 w8 c9 c9"),
+				WinningTile: mustParseTile("b4"),
+				Rules:       mj.Ruleset{Name: "16 tiles", HandSize: 16, Scoring: "singapore"},
+			},
+			0,
+			false,
+			ErrInvalidHand,
+		},
+		{
+			"not pinghu, closed wait",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b2 b3 b4 b5 b6 c2 c3 c4 w6 w7 w8 c9 c9"),
+				Bonus:       mj.MustParseHand("f2"),
+				WinningTile: mustParseTile("b2"),
+				Seat:        mj.East,
+				Round:       mj.East,
+			},
+			0,
+			false,
+			nil,
+		},
+		{
+			"seat flower, animals and dragon",
+			Win{
+				Concealed:   mj.MustParseHand("hz hz hz b4 b5 b6 c2 c3 c4 w6 w7 w8 c9 c9"),
+				Bonus:       mj.MustParseHand("f2 f6 a3 a4"),
+				WinningTile: mustParseTile("c9"),
+				Seat:        mj.South,
+				Round:       mj.East,
+			},
+			// 2 seat flowers, 2 animals, cat and mouse, dragon, capped at 5
+			5,
+			true,
+			nil,
+		},
+		{
+			"half colour with melds",
+			Win{
+				Concealed: mj.MustParseHand("b1 b1 b1 b2 b3 b4 hs hs"),
+				Melds: []mj.Meld{
+					{Kind: mj.Peng, Tile: mustParseTile("he")},
+					{Kind: mj.Chi, Tile: mustParseTile("b7")},
+				},
+				Bonus:       mj.MustParseHand("f3"),
+				WinningTile: mustParseTile("hs"),
+				Seat:        mj.North,
+				Round:       mj.South,
+			},
+			// half colour 2, east is neither seat nor round wind
+			2,
+			false,
+			nil,
+		},
+		{
+			"thirteen orphans",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b9 c1 c9 w1 w9 he hs hw hn hz hf hb hb"),
+				Bonus:       mj.MustParseHand("f1"),
+				WinningTile: mustParseTile("hb"),
+				Seat:        mj.West,
+				Round:       mj.East,
+			},
+			5,
+			true,
+			nil,
+		},
+		{
+			"seven pairs does not win",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b1 b3 b3 c4 c4 c7 c7 w2 w2 hn hn hz hz"),
+				WinningTile: mustParseTile("hz"),
+				Seat:        mj.West,
+				Round:       mj.East,
+			},
+			0,
+			false,
+			ErrNotWinning,
+		},
+		{
+			"wrong size",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b1 b1"),
+				WinningTile: mustParseTile("b1"),
+			},
+			0,
+			false,
+			ErrInvalidHand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SingaporeScorer{}.Score(tt.win)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Score() err = %v, want %v", err, tt.wantErr)
+			}
+			if got.Total != tt.wantTotal || got.Limit != tt.wantLimit {
+				t.Errorf("Score() = %v, want total %d limit %t", got, tt.wantTotal, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestSingaporeBonus(t *testing.T) {
+	tests := []struct {
+		name  string
+		have  mj.Hand
+		drawn mj.Tile
+		want  []Bonus
+	}{
+		{"nothing", nil, mustParseTile("a1"), nil},
+		{"cat and mouse", mj.MustParseHand("a4 f1"), mustParseTile("a3"), []Bonus{{"Cat and mouse", 1}}},
+		{
+			"all animals",
+			mj.MustParseHand("a1 a2 a3"),
+			mustParseTile("a4"),
+			[]Bonus{{"Cat and mouse", 1}, {"All animals", 2}},
+		},
+		{"all seasons", mj.MustParseHand("f5 f6 f8 f1"), mustParseTile("f7"), []Bonus{{"All seasons", 2}}},
+		{"not all flowers", mj.MustParseHand("f5 f6 f8"), mustParseTile("f1"), nil},
+		{"melding tile", mj.MustParseHand("a3"), mustParseTile("b1"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SingaporeBonus(tt.have, tt.drawn); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SingaporeBonus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
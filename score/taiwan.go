@@ -0,0 +1,197 @@
+package score
+
+import (
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/wait"
+)
+
+// TaiwaneseScorer scores 16-tile hands in tai according to common Taiwanese rules.
+// A winning hand has 17 tiles: five sets and a pair, or seven pairs and a set of three
+// identical tiles. The zero value is ready to use.
+//
+// The following elements are scored:
+//  - 1 tai for the dealer, plus 2 tai for each hand in the dealer's streak
+//  - 1 tai for a concealed hand won on a discard, 1 tai for self-drawn, or 3 tai for both
+//  - 1 tai for each dragon pung, and for pungs of the seat and prevailing winds
+//  - 1 tai for each flower or season matching the winner's seat, and 2 tai for a full set
+//    of four flowers or four seasons
+//  - 2 tai for pinghu: all chis, no honours or flowers, two-sided wait, won on a discard
+//  - 2 tai for all melds claimed, won on a discard with a single wait
+//  - 2, 5 and 8 tai for three, four and five concealed pungs
+//  - 4 tai for all pungs
+//  - 4 tai for half flush, 8 tai for full flush and 16 tai for all honours
+//  - 4 tai for small three dragons, 8 tai for big three dragons
+//  - 8 tai for small four winds, 16 tai for big four winds
+//  - 1 tai each for winning on the last tile, after a gang, by robbing a gang, or with
+//    only one waited tile
+//  - 8 tai for seven pairs and a set of three identical tiles
+//  - 8 tai for all eight bonus tiles
+//  - 16 tai for winning on the first draw
+type TaiwaneseScorer struct{}
+
+// Score finds the highest scoring interpretation of a winning hand.
+func (s TaiwaneseScorer) Score(w Win) (Breakdown, error) {
+	if len(w.Bonus) == 8 {
+		// all eight flowers wins immediately, whatever the hand is
+		var b Breakdown
+		b.add("Eight immortals cross the sea", 8)
+		return b, nil
+	}
+
+	w = w.withRules(mj.Taiwanese)
+	if err := w.validate(w.Rules.Size()); err != nil {
+		return Breakdown{}, err
+	}
+
+	as := w.arrangements()
+	waits := completingTiles(w)
+
+	return best(as, func(a arrangement) (Breakdown, bool) {
+		return s.score(w, a, waits), true
+	})
+}
+
+func (TaiwaneseScorer) score(w Win, a arrangement, waits int) Breakdown {
+	var b Breakdown
+
+	if w.Seat == mj.East {
+		b.add("Dealer", 1)
+		if w.DealerStreak > 0 {
+			b.add("Dealer streak", 2*w.DealerStreak)
+		}
+	}
+
+	exposed := 0
+	for _, m := range w.Melds {
+		if !m.Concealed {
+			exposed++
+		}
+	}
+	switch {
+	case exposed == 0 && w.SelfDrawn:
+		b.add("Concealed self-drawn", 3)
+	case exposed == 0:
+		b.add("Concealed hand", 1)
+	case w.SelfDrawn:
+		b.add("Self-drawn", 1)
+	}
+
+	if w.FirstTurn {
+		if w.Seat == mj.East {
+			b.add("Heavenly hand", 16)
+		} else {
+			b.add("Earthly hand", 16)
+		}
+	}
+	switch {
+	case w.LastWallTile && w.SelfDrawn:
+		b.add("Last tile draw", 1)
+	case w.LastWallTile:
+		b.add("Last tile claim", 1)
+	}
+	if w.AfterGang {
+		b.add("Win after gang", 1)
+	}
+	if w.RobbedGang {
+		b.add("Robbing the gang", 1)
+	}
+
+	taiwaneseBonus(&b, w)
+
+	ss, honours := suits(a.tiles)
+	switch {
+	case len(ss) == 0:
+		b.add("All honours", 16)
+	case len(ss) == 1 && honours:
+		b.add("Half flush", 4)
+	case len(ss) == 1:
+		b.add("Full flush", 8)
+	}
+
+	if a.special == "ligu ligu" {
+		b.add("Seven pairs and a pung", 8)
+		return b
+	}
+
+	if waits == 1 && a.wait != wait.TwoSided && a.wait != wait.DualPung {
+		b.add("Single wait", 1)
+	}
+
+	dragons, winds, concealed := 0, 0, 0
+	for _, m := range a.sets {
+		if m.Kind == mj.Chi {
+			continue
+		}
+		if m.Concealed {
+			concealed++
+		}
+		if isDragon(m.Tile) {
+			dragons++
+			b.add("Dragon pung", 1)
+		}
+		if isWind(m.Tile) {
+			winds++
+			if m.Tile.Value == w.Seat {
+				b.add("Seat wind", 1)
+			}
+			if m.Tile.Value == w.Round {
+				b.add("Prevailing wind", 1)
+			}
+		}
+	}
+
+	switch {
+	case dragons == 3:
+		b.add("Big three dragons", 8)
+	case dragons == 2 && isDragon(a.pair):
+		b.add("Small three dragons", 4)
+	}
+	switch {
+	case winds == 4:
+		b.add("Big four winds", 16)
+	case winds == 3 && isWind(a.pair):
+		b.add("Small four winds", 8)
+	}
+
+	switch concealed {
+	case 5:
+		b.add("Five concealed pungs", 8)
+	case 4:
+		b.add("Four concealed pungs", 5)
+	case 3:
+		b.add("Three concealed pungs", 2)
+	}
+
+	chis := a.count(mj.Chi)
+	switch {
+	case chis == 0:
+		b.add("All pungs", 4)
+	case chis == len(a.sets) && !honours && len(w.Bonus) == 0 && !w.SelfDrawn &&
+		a.wait == wait.TwoSided:
+		b.add("Pinghu", 2)
+	}
+
+	if exposed == len(a.sets) && !w.SelfDrawn && a.wait == wait.Single {
+		b.add("All melds claimed", 2)
+	}
+
+	return b
+}
+
+// taiwaneseBonus adds the tai for flowers and seasons.
+func taiwaneseBonus(b *Breakdown, w Win) {
+	seat := seatIndex(w.Seat)
+	had := make(map[mj.Value]bool)
+	for _, t := range w.Bonus {
+		had[t.Value] = true
+		if int(t.Value-mj.FlowerBase)%4 == seat {
+			b.add("Seat flower", 1)
+		}
+	}
+	if countTrue(had, mj.FlowerBase, mj.FlowerBase+3) == 4 {
+		b.add("All flowers", 2)
+	}
+	if countTrue(had, mj.FlowerBase+4, mj.FlowerBase+7) == 4 {
+		b.add("All seasons", 2)
+	}
+}
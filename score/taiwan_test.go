@@ -0,0 +1,120 @@
+package score
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestTaiwaneseScorer_Score(t *testing.T) {
+	tests := []struct {
+		name      string
+		win       Win
+		wantTotal int
+		wantErr   error
+	}{
+		{
+			"pinghu",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b2 b3 b4 b5 b6 c2 c3 c4 c5 c6 c7 w6 w7 w8 c9 c9"),
+				WinningTile: mustParseTile("b4"),
+				Seat:        mj.South,
+				Round:       mj.East,
+			},
+			// concealed 1 + pinghu 2
+			3,
+			nil,
+		},
+		{
+			"custom 16-tile rules",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b2 b3 b4 b5 b6 c2 c3 c4 c5 c6 c7 w6 w7 w8 c9 c9"),
+				WinningTile: mustParseTile("b4"),
+				Seat:        mj.South,
+				Round:       mj.East,
+				Rules:       mj.Ruleset{Name: "no seasons", HandSize: 16, Tiles: mj.TileSet{NoSeasons: true}},
+			},
+			3,
+			nil,
+		},
+		{
+			"13-tile rules",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b2 b3 b4 b5 b6 c2 c3 c4 c5 c6 c7 w6 w7 w8 c9 c9"),
+				WinningTile: mustParseTile("b4"),
+				Rules:       mj.HongKong,
+			},
+			0,
+			ErrInvalidHand,
+		},
+		{
+			"seven pairs and a pung",
+			Win{
+				Concealed: mj.MustParseHand(
+					"b1 b1 b3 b3 c4 c4 c7 c7 w2 w2 w5 w5 hn hn hz hz hz"),
+				WinningTile: mustParseTile("hz"),
+				SelfDrawn:   true,
+				Seat:        mj.West,
+				Round:       mj.East,
+			},
+			// concealed self-drawn 3 + ligu ligu 8
+			11,
+			nil,
+		},
+		{
+			"dealer streak with melds",
+			Win{
+				Concealed: mj.MustParseHand("b1 b2 b3 b4 b5 b6 c7 c8 c9 w5 w5"),
+				Melds: []mj.Meld{
+					{Kind: mj.Peng, Tile: mustParseTile("hz")},
+					{Kind: mj.Peng, Tile: mustParseTile("he")},
+				},
+				WinningTile:  mustParseTile("w5"),
+				SelfDrawn:    true,
+				Seat:         mj.East,
+				Round:        mj.South,
+				DealerStreak: 2,
+			},
+			// dealer 1 + streak 4 + self-drawn 1 + dragon 1 + seat wind 1 + single wait 1
+			9,
+			nil,
+		},
+		{
+			"eight flowers",
+			Win{Bonus: mj.MustParseHand("f1 f2 f3 f4 f5 f6 f7 f8")},
+			8,
+			nil,
+		},
+		{
+			"five of a tile",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b1 b1 b1 b1 b2 b3 c2 c3 c4 c5 c6 c7 w6 w7 w8 c9"),
+				WinningTile: mustParseTile("b1"),
+			},
+			0,
+			ErrInvalidHand,
+		},
+		{
+			"13-tile hand",
+			Win{
+				Concealed:   mj.MustParseHand("b1 b2 b3 b4 b5 b6 c2 c3 c4 w6 w7 w8 c9 c9"),
+				WinningTile: mustParseTile("b4"),
+			},
+			0,
+			ErrInvalidHand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TaiwaneseScorer{}.Score(tt.win)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Score() err = %v, want %v", err, tt.wantErr)
+			}
+			if got.Total != tt.wantTotal {
+				t.Errorf("Score() = %v, want total %d", got, tt.wantTotal)
+			}
+		})
+	}
+}
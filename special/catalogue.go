@@ -0,0 +1,289 @@
+package special
+
+import (
+	"sort"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+)
+
+const (
+	ThirteenOrphans Kind = iota + 1
+	SevenPairs
+	NineGates
+	AllHonours
+	AllTerminals
+	MixedTerminals
+	AllGreen
+	GreatThreeDragons
+	SmallThreeDragons
+	GreatFourWinds
+	SmallFourWinds
+	PureOneSuit
+	MixedOneSuit
+	AllPungs
+	AllConcealed
+	EighteenArhats
+)
+
+// Kind is one of the special hands in the catalogue.
+type Kind int
+
+var kindNames = map[Kind]string{
+	ThirteenOrphans:   "thirteen orphans",
+	SevenPairs:        "seven pairs",
+	NineGates:         "nine gates",
+	AllHonours:        "all honours",
+	AllTerminals:      "all terminals",
+	MixedTerminals:    "mixed terminals",
+	AllGreen:          "all green",
+	GreatThreeDragons: "great three dragons",
+	SmallThreeDragons: "small three dragons",
+	GreatFourWinds:    "great four winds",
+	SmallFourWinds:    "small four winds",
+	PureOneSuit:       "pure one suit",
+	MixedOneSuit:      "mixed one suit",
+	AllPungs:          "all pungs",
+	AllConcealed:      "all concealed",
+	EighteenArhats:    "eighteen arhats",
+}
+
+func (k Kind) String() string {
+	if s, ok := kindNames[k]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+// SpecialHand is a special hand that a hand is, or is waiting to be.
+type SpecialHand struct {
+	Kind Kind
+	// Waits holds the tiles that complete the special hand, in sorted order. It is nil
+	// for complete hands.
+	Waits []mj.Tile
+}
+
+// greenTiles holds the tiles allowed in an all green hand.
+var greenTiles = mj.MustParseHand("b2 b3 b4 b6 b8 hf")
+
+// FindComplete returns every special hand in the catalogue that a winning hand is,
+// in the order of their Kinds. The hand is given as its concealed tiles, including the
+// winning tile, and its declared melds. Thirteen orphans and seven pairs are only
+// found if the Ruleset allows them. If the hand is not a winning hand, FindComplete
+// returns nil.
+func FindComplete(concealed mj.Hand, melds []mj.Meld, rs mj.Ruleset) []SpecialHand {
+	var out []SpecialHand
+	add := func(k Kind, ok bool) {
+		if ok {
+			out = append(out, SpecialHand{Kind: k})
+		}
+	}
+
+	tiles := make(mj.Hand, len(concealed))
+	copy(tiles, concealed)
+	for _, m := range melds {
+		tiles = append(tiles, m.Tiles()...)
+	}
+	if !tiles.Valid() {
+		return nil
+	}
+
+	orphans := len(melds) == 0 && isCompleteThirteenOrphans(concealed, rs)
+	pairs := len(melds) == 0 && isCompleteSevenPairs(concealed, rs)
+	groups := handcheck.CompleteWithRules(concealed, rs)
+	if len(groups) > 0 && len(groups[0].Pengs)+len(groups[0].Chis)+len(melds) != rs.Sets() {
+		groups = nil
+	}
+	if !orphans && !pairs && len(groups) == 0 {
+		return nil
+	}
+
+	var honours, terminals, simples, green int
+	suits := make(map[mj.Suit]bool)
+	greenCnt := greenTiles.ToCount()
+	for _, t := range tiles {
+		switch {
+		case t.Suit == mj.Honour:
+			honours++
+		case t.IsTerminal():
+			terminals++
+		default:
+			simples++
+		}
+		if t.IsBasic() {
+			suits[t.Suit] = true
+		}
+		if greenCnt.Get(t) > 0 {
+			green++
+		}
+	}
+
+	add(ThirteenOrphans, orphans)
+	add(SevenPairs, pairs)
+	add(NineGates, len(melds) == 0 && isNineGates(concealed))
+	add(AllHonours, honours == len(tiles))
+	add(AllTerminals, terminals == len(tiles))
+	add(MixedTerminals, !orphans && simples == 0 && honours > 0 && terminals > 0)
+	add(AllGreen, green == len(tiles))
+
+	// the rest need sets, and any grouping of the hand will do
+	found := make(map[Kind]bool)
+	for _, g := range groups {
+		for _, k := range setKinds(g, melds) {
+			found[k] = true
+		}
+	}
+	add(GreatThreeDragons, found[GreatThreeDragons])
+	add(SmallThreeDragons, found[SmallThreeDragons])
+	add(GreatFourWinds, found[GreatFourWinds])
+	add(SmallFourWinds, found[SmallFourWinds])
+
+	add(PureOneSuit, len(suits) == 1 && honours == 0)
+	add(MixedOneSuit, len(suits) == 1 && honours > 0)
+	add(AllPungs, found[AllPungs])
+
+	concealedOnly, gangs := true, 0
+	for _, m := range melds {
+		concealedOnly = concealedOnly && m.Concealed
+		if m.Kind == mj.Gang {
+			gangs++
+		}
+	}
+	add(AllConcealed, concealedOnly)
+	add(EighteenArhats, gangs == 4)
+
+	return out
+}
+
+// FindWaiting returns every special hand in the catalogue that a waiting hand could
+// become, together with the tiles that would complete it. Tiles that are all visible
+// in the hand already are not considered.
+func FindWaiting(concealed mj.Hand, melds []mj.Meld, rs mj.Ruleset) []SpecialHand {
+	all := make(mj.Hand, len(concealed))
+	copy(all, concealed)
+	for _, m := range melds {
+		all = append(all, m.Tiles()...)
+	}
+	cnt := all.ToCount()
+
+	waits := make(map[Kind][]mj.Tile)
+	for _, t := range rs.Tiles.Unique() {
+		if !t.CanMeld() || cnt.Get(t) >= rs.Tiles.Count(t) {
+			continue
+		}
+		for _, s := range FindComplete(concealed.Append(t), melds, rs) {
+			waits[s.Kind] = append(waits[s.Kind], t)
+		}
+	}
+
+	out := make([]SpecialHand, 0, len(waits))
+	for k, ts := range waits {
+		out = append(out, SpecialHand{Kind: k, Waits: ts})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Kind < out[j].Kind })
+	return out
+}
+
+// setKinds returns the special hands that depend on the sets of a grouping.
+func setKinds(g mj.Group, melds []mj.Meld) []Kind {
+	pungs := make(mj.Hand, len(g.Pengs))
+	copy(pungs, g.Pengs)
+	chis := len(g.Chis)
+	for _, m := range melds {
+		if m.Kind == mj.Chi {
+			chis++
+		} else {
+			pungs = append(pungs, m.Tile)
+		}
+	}
+
+	dragons, winds := 0, 0
+	for _, t := range pungs {
+		switch {
+		case isDragon(t):
+			dragons++
+		case isWind(t):
+			winds++
+		}
+	}
+	pair := g.Pairs[0]
+
+	var out []Kind
+	switch {
+	case dragons == 3:
+		out = append(out, GreatThreeDragons)
+	case dragons == 2 && isDragon(pair):
+		out = append(out, SmallThreeDragons)
+	}
+	switch {
+	case winds == 4:
+		out = append(out, GreatFourWinds)
+	case winds == 3 && isWind(pair):
+		out = append(out, SmallFourWinds)
+	}
+	if chis == 0 {
+		out = append(out, AllPungs)
+	}
+	return out
+}
+
+// isCompleteThirteenOrphans returns true if removing some tile from the hand leaves
+// a hand waiting on that tile for thirteen orphans.
+func isCompleteThirteenOrphans(hand mj.Hand, rs mj.Ruleset) bool {
+	for i, t := range hand {
+		if ok, wait := IsThirteenOrphansWithRules(hand.Remove(i), rs); ok && (!wait.Valid() || wait == t) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCompleteSevenPairs returns true if removing some tile from the hand leaves
+// a hand waiting on that tile for seven pairs.
+func isCompleteSevenPairs(hand mj.Hand, rs mj.Ruleset) bool {
+	for i, t := range hand {
+		ok, waits := IsSevenPairsWithRules(hand.Remove(i), rs)
+		if !ok {
+			continue
+		}
+		for _, w := range waits {
+			if w == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNineGates returns true for a concealed hand of 1112345678999 in one suit plus
+// any other tile of that suit.
+func isNineGates(hand mj.Hand) bool {
+	if len(hand) != 14 {
+		return false
+	}
+	var counts [10]int
+	for _, t := range hand {
+		if !t.IsBasic() || t.Suit != hand[0].Suit {
+			return false
+		}
+		counts[t.Value]++
+	}
+	for v := 1; v <= 9; v++ {
+		need := 1
+		if v == 1 || v == 9 {
+			need = 3
+		}
+		if counts[v] < need {
+			return false
+		}
+	}
+	return true
+}
+
+func isDragon(t mj.Tile) bool {
+	return t.Suit == mj.Honour && t.Value >= mj.Zhong
+}
+
+func isWind(t mj.Tile) bool {
+	return t.Suit == mj.Honour && t.Value <= mj.North
+}
@@ -0,0 +1,111 @@
+package special
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestFindComplete(t *testing.T) {
+	tests := []struct {
+		name      string
+		concealed string
+		melds     []mj.Meld
+		want      []Kind
+	}{
+		{
+			"not winning",
+			"b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz hf hf hb",
+			nil,
+			nil,
+		},
+		{
+			"plain",
+			"b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz hz hf hf",
+			nil,
+			[]Kind{AllConcealed},
+		},
+		{
+			"thirteen orphans",
+			"b1 b9 c1 c9 w1 w9 he hs hw hn hz hf hb hb",
+			nil,
+			[]Kind{ThirteenOrphans, AllConcealed},
+		},
+		{
+			"seven pairs all honours",
+			"he he hs hs hw hw hn hn hz hz hf hf hb hb",
+			nil,
+			[]Kind{SevenPairs, AllHonours, AllConcealed},
+		},
+		{
+			"nine gates",
+			"c1 c1 c1 c2 c3 c4 c5 c5 c6 c7 c8 c9 c9 c9",
+			nil,
+			[]Kind{NineGates, PureOneSuit, AllConcealed},
+		},
+		{
+			"all terminals",
+			"b1 b1 b1 b9 b9 b9 c1 c1 c1 w9 w9",
+			[]mj.Meld{{Kind: mj.Peng, Tile: mj.Tile{Suit: mj.Coin, Value: 9}}},
+			[]Kind{AllTerminals, AllPungs},
+		},
+		{
+			"great three dragons",
+			"hz hz hz hf hf hf hb hb hb b1 b2 b3 b5 b5",
+			nil,
+			[]Kind{GreatThreeDragons, MixedOneSuit, AllConcealed},
+		},
+		{
+			"small four winds",
+			"he he he hs hs hs hw hw hw hn hn w1 w1 w1",
+			nil,
+			[]Kind{MixedTerminals, SmallFourWinds, MixedOneSuit, AllPungs, AllConcealed},
+		},
+		{
+			"all green",
+			"b2 b3 b4 b2 b3 b4 b6 b6 b6 b8 b8 hf hf hf",
+			nil,
+			[]Kind{AllGreen, MixedOneSuit, AllConcealed},
+		},
+		{
+			"eighteen arhats",
+			"hb hb",
+			[]mj.Meld{
+				{Kind: mj.Gang, Tile: mj.Tile{Suit: mj.Bamboo, Value: 1}},
+				{Kind: mj.Gang, Tile: mj.Tile{Suit: mj.Coin, Value: 5}, Concealed: true},
+				{Kind: mj.Gang, Tile: mj.Tile{Suit: mj.Wan, Value: 9}},
+				{Kind: mj.Gang, Tile: mj.Tile{Suit: mj.Honour, Value: mj.East}},
+			},
+			[]Kind{AllPungs, EighteenArhats},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Kind
+			for _, s := range FindComplete(mj.MustParseHand(tt.concealed), tt.melds, mj.HongKong) {
+				got = append(got, s.Kind)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindComplete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindWaiting(t *testing.T) {
+	got := FindWaiting(mj.MustParseHand("c1 c1 c1 c2 c3 c4 c5 c6 c7 c8 c9 c9 c9"), nil, mj.HongKong)
+	want := map[Kind]string{
+		NineGates:    "c1 c2 c3 c4 c5 c6 c7 c8 c9",
+		PureOneSuit:  "c1 c2 c3 c4 c5 c6 c7 c8 c9",
+		AllConcealed: "c1 c2 c3 c4 c5 c6 c7 c8 c9",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("FindWaiting() = %v", got)
+	}
+	for _, s := range got {
+		if w, ok := want[s.Kind]; !ok || mj.Hand(s.Waits).Marshal() != mj.MustParseHand(w).Marshal() {
+			t.Errorf("%v waits on %v, want %s", s.Kind, mj.Hand(s.Waits), w)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package special
+
+import (
+	"sort"
+
+	"github.com/nik0sc/mj"
+)
+
+// knittedStraights holds the 6 possible knitted straights, one for each way to assign
+// 1-4-7, 2-5-8 and 3-6-9 to the three basic suits.
+var knittedStraights = func() []mj.Hand {
+	perms := [][3]mj.Suit{
+		{mj.Bamboo, mj.Coin, mj.Wan},
+		{mj.Bamboo, mj.Wan, mj.Coin},
+		{mj.Coin, mj.Bamboo, mj.Wan},
+		{mj.Coin, mj.Wan, mj.Bamboo},
+		{mj.Wan, mj.Bamboo, mj.Coin},
+		{mj.Wan, mj.Coin, mj.Bamboo},
+	}
+
+	out := make([]mj.Hand, len(perms))
+	for i, p := range perms {
+		for j, s := range p {
+			for v := mj.Value(j + 1); v <= 9; v += 3 {
+				out[i] = append(out[i], mj.Tile{Suit: s, Value: v})
+			}
+		}
+		sort.Sort(out[i])
+	}
+	return out
+}()
+
+// FindKnittedStraight returns true if the hand contains a knitted straight: 1-4-7 in one
+// suit, 2-5-8 in another and 3-6-9 in the third. Additionally, the tiles left over after
+// removing the knitted straight are returned in sorted order.
+func FindKnittedStraight(hand mj.Hand) (ok bool, rest mj.Hand) {
+	cnt := hand.ToCount()
+
+	for _, ks := range knittedStraights {
+		c := cnt
+		found := true
+		for _, t := range ks {
+			if c.Get(t) == 0 {
+				found = false
+				break
+			}
+			c = c.Remove(t)
+		}
+		if found {
+			return true, c.ToHand(true)
+		}
+	}
+	return false, nil
+}
+
+// IsHonoursAndKnitted returns true if the hand is a complete Honours and Knitted Tiles hand:
+// 14 different tiles taken from one knitted straight and the seven honours. Additionally,
+// greater is true if all seven honours are present.
+func IsHonoursAndKnitted(hand mj.Hand) (ok bool, greater bool) {
+	if len(hand) != 14 {
+		return
+	}
+
+	cnt := hand.ToCount()
+	honours := 0
+	var basics mj.Hand
+	for _, e := range cnt.Entries() {
+		if e.Count != 1 {
+			return false, false
+		}
+		switch {
+		case e.Tile.Suit == mj.Honour:
+			honours++
+		case e.Tile.IsBasic():
+			basics = append(basics, e.Tile)
+		default:
+			return false, false
+		}
+	}
+
+	for _, ks := range knittedStraights {
+		kcnt := ks.ToCount()
+		within := true
+		for _, t := range basics {
+			if kcnt.Get(t) == 0 {
+				within = false
+				break
+			}
+		}
+		if within {
+			return true, honours == 7
+		}
+	}
+	return false, false
+}
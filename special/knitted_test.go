@@ -0,0 +1,111 @@
+package special
+
+import (
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestFindKnittedStraight(t *testing.T) {
+	tests := []struct {
+		name     string
+		hand     mj.Hand
+		wantOk   bool
+		wantRest mj.Hand
+	}{
+		{
+			"Complete",
+			mj.MustParseHand("b1 b4 b7 c2 c5 c8 w3 w6 w9 hz hz hz he he"),
+			true,
+			mj.MustParseHand("he he hz hz hz"),
+		},
+		{
+			"Other suits",
+			mj.MustParseHand("w1 w4 w7 b2 b5 b8 c3 c6 c9 c1 c2 c3 c5 c5"),
+			true,
+			mj.MustParseHand("c1 c2 c3 c5 c5"),
+		},
+		{
+			"Only knitted",
+			mj.MustParseHand("b1 b4 b7 c2 c5 c8 w3 w6 w9"),
+			true,
+			mj.Hand{},
+		},
+		{
+			"Missing 1",
+			mj.MustParseHand("b1 b4 b7 c2 c5 c8 w3 w6 w8 hz hz hz he he"),
+			false,
+			nil,
+		},
+		{
+			"Same suit",
+			mj.MustParseHand("b1 b4 b7 b2 b5 b8 b3 b6 b9 hz hz hz he he"),
+			false,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOk, gotRest := FindKnittedStraight(tt.hand)
+			if gotOk != tt.wantOk {
+				t.Errorf("FindKnittedStraight() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+			if gotRest.Marshal() != tt.wantRest.Marshal() {
+				t.Errorf("FindKnittedStraight() gotRest = %v, want %v", gotRest, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestIsHonoursAndKnitted(t *testing.T) {
+	tests := []struct {
+		name        string
+		hand        mj.Hand
+		wantOk      bool
+		wantGreater bool
+	}{
+		{
+			"Lesser",
+			mj.MustParseHand("b1 b4 b7 c2 c5 w3 w6 w9 he hs hw hz hf hb"),
+			true,
+			false,
+		},
+		{
+			"Greater",
+			mj.MustParseHand("b1 b7 c5 c8 w3 w6 w9 he hs hw hn hz hf hb"),
+			true,
+			true,
+		},
+		{
+			"Not knitted",
+			mj.MustParseHand("b1 b2 b7 c2 c5 w3 w6 w9 he hs hw hz hf hb"),
+			false,
+			false,
+		},
+		{
+			"Repeated",
+			mj.MustParseHand("b1 b4 b7 c2 c5 w3 w6 w9 he hs hw hz hz hb"),
+			false,
+			false,
+		},
+		{
+			"Short",
+			mj.MustParseHand("b1 b4 b7 c2 c5 w3 w6 w9 he hs hw hz hf"),
+			false,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOk, gotGreater := IsHonoursAndKnitted(tt.hand)
+			if gotOk != tt.wantOk {
+				t.Errorf("IsHonoursAndKnitted() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+			if gotGreater != tt.wantGreater {
+				t.Errorf("IsHonoursAndKnitted() gotGreater = %v, want %v", gotGreater, tt.wantGreater)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package special
+
+import (
+	"sort"
+
+	"github.com/nik0sc/mj"
+)
+
+// Wait is a tile that completes a special hand.
+type Wait struct {
+	Tile mj.Tile
+	// Discard is the tile to discard from a complete hand to wait on Tile. It is the zero
+	// Tile for waiting hands.
+	Discard mj.Tile
+	// Remaining is the number of copies of Tile that are not in the hand or visible.
+	Remaining int
+}
+
+// ThirteenOrphansWaits is like IsThirteenOrphans, but returns every waited tile, so a
+// pure hand waits on all 13 tiles. It accepts a waiting hand with the Ruleset's hand size,
+// or a hand with one more tile, in which case the waits after each possible discard are
+// returned. Remaining counts are worked out from the Ruleset's tileset, the hand, and
+// the visible tiles, which may be the zero Counter.
+func ThirteenOrphansWaits(hand mj.Hand, visible mj.Counter, rs mj.Ruleset) []Wait {
+	return specialWaits(hand, visible, rs, func(h mj.Hand) []mj.Tile {
+		ok, wait := IsThirteenOrphansWithRules(h, rs)
+		switch {
+		case !ok:
+			return nil
+		case !wait.Valid():
+			return thirteenPure
+		default:
+			return []mj.Tile{wait}
+		}
+	})
+}
+
+// SevenPairsWaits is like IsSevenPairsWithRules, but accepts complete hands and reports
+// remaining counts in the same way as ThirteenOrphansWaits.
+func SevenPairsWaits(hand mj.Hand, visible mj.Counter, rs mj.Ruleset) []Wait {
+	return specialWaits(hand, visible, rs, func(h mj.Hand) []mj.Tile {
+		_, waits := IsSevenPairsWithRules(h, rs)
+		return waits
+	})
+}
+
+// specialWaits calls find on the waiting hand, or on each waiting hand that can be
+// reached by one discard from a complete hand.
+func specialWaits(hand mj.Hand, visible mj.Counter, rs mj.Ruleset, find func(mj.Hand) []mj.Tile) []Wait {
+	cnt := hand.ToCount()
+	var out []Wait
+	add := func(waits []mj.Tile, discard mj.Tile) {
+		for _, t := range waits {
+			remaining := rs.Tiles.Count(t) - cnt.Get(t) - visible.Get(t)
+			if remaining < 0 {
+				remaining = 0
+			}
+			out = append(out, Wait{Tile: t, Discard: discard, Remaining: remaining})
+		}
+	}
+
+	switch len(hand) {
+	case rs.Size():
+		add(find(hand), mj.Tile{})
+	case rs.Size() + 1:
+		discards := cnt.ToHand(true)
+		for i, d := range discards {
+			if i > 0 && discards[i-1] == d {
+				continue
+			}
+			h := make(mj.Hand, 0, len(hand)-1)
+			removed := false
+			for _, t := range hand {
+				if t == d && !removed {
+					removed = true
+					continue
+				}
+				h = append(h, t)
+			}
+			add(find(h), d)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Discard != out[j].Discard {
+			return out[i].Discard.Less(out[j].Discard)
+		}
+		return out[i].Tile.Less(out[j].Tile)
+	})
+	return out
+}
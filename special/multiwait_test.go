@@ -0,0 +1,86 @@
+package special
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestThirteenOrphansWaits(t *testing.T) {
+	visible := mj.MustParseHand("b1 b1 hb hb hb hb").ToCount()
+
+	got := ThirteenOrphansWaits(mj.MustParseHand("b1 b9 c1 c9 w1 w9 he hs hw hn hz hf hb"), visible, mj.HongKong)
+	if len(got) != 13 {
+		t.Fatalf("got %d waits, want 13: %v", len(got), got)
+	}
+	if got[0].Tile != thirteenPure[0] || got[0].Remaining != 1 {
+		t.Errorf("got %+v, want 1 b1 remaining", got[0])
+	}
+	if got[12].Tile != thirteenPure[12] || got[12].Remaining != 0 {
+		t.Errorf("got %+v, want no hb remaining", got[12])
+	}
+
+	got = ThirteenOrphansWaits(mj.MustParseHand("b1 b9 c1 c9 w1 w9 he hs hw hn hz hf hf b5"), mj.Counter{}, mj.HongKong)
+	want := []Wait{{Tile: mj.Tile{Suit: mj.Honour, Value: mj.Ban}, Discard: mj.Tile{Suit: mj.Bamboo, Value: 5}, Remaining: 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestSevenPairsWaits(t *testing.T) {
+	tests := []struct {
+		name string
+		hand string
+		rs   mj.Ruleset
+		want []Wait
+	}{
+		{
+			"waiting",
+			"b1 b1 b2 b2 b3 b3 b4 b4 b5 b5 b6 b6 b7",
+			mj.HongKong,
+			[]Wait{{Tile: mj.Tile{Suit: mj.Bamboo, Value: 7}, Remaining: 3}},
+		},
+		{
+			"complete",
+			"b1 b1 b2 b2 b3 b3 b4 b4 b5 b5 b6 b6 b7 b8",
+			mj.HongKong,
+			[]Wait{
+				{Tile: mj.Tile{Suit: mj.Bamboo, Value: 8}, Discard: mj.Tile{Suit: mj.Bamboo, Value: 7}, Remaining: 3},
+				{Tile: mj.Tile{Suit: mj.Bamboo, Value: 7}, Discard: mj.Tile{Suit: mj.Bamboo, Value: 8}, Remaining: 3},
+			},
+		},
+		{
+			"repeat",
+			"c1 c1 c2 c2 c2 w1 w1 hf hf w2 w2 w3 w3",
+			mj.MCR,
+			[]Wait{{Tile: mj.Tile{Suit: mj.Coin, Value: 2}, Remaining: 1}},
+		},
+		{
+			"ligu",
+			"b1 b1 b2 b2 b3 b3 b4 b4 b5 b5 b6 b6 b7 b7 hz hz",
+			mj.Taiwanese,
+			nil,
+		},
+		{
+			"wrong size",
+			"b1 b1",
+			mj.HongKong,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SevenPairsWaits(mj.MustParseHand(tt.hand), mj.Counter{}, tt.rs)
+			if tt.name == "ligu" {
+				if len(got) != 8 {
+					t.Errorf("got %d waits, want 8: %+v", len(got), got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
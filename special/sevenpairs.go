@@ -1,6 +1,10 @@
 package special
 
-import "github.com/nik0sc/mj"
+import (
+	"sort"
+
+	"github.com/nik0sc/mj"
+)
 
 // IsSevenPairs returns true if the hand is in a waiting state for
 // the Seven Pairs win (six pairs and one tile that could be waited).
@@ -44,3 +48,61 @@ func IsSevenPairs(hand mj.Hand, allowRepeat bool) (ok bool, wait mj.Tile) {
 
 	return
 }
+
+// IsSevenPairsWithRules is like IsSevenPairs, but for the hand size and repeat policy
+// of the Ruleset. In 16-tile play, the equivalent hand is seven pairs and a set of
+// three identical tiles (the Taiwanese "ligu ligu"). Such a hand may wait on several
+// tiles, so all the waited tiles are returned. Other hand sizes have no seven pairs
+// hand, and neither does a Ruleset that does not allow it.
+func IsSevenPairsWithRules(hand mj.Hand, rs mj.Ruleset) (ok bool, waits []mj.Tile) {
+	if !rs.Allows(mj.SevenPairsHand) {
+		return false, nil
+	}
+	allowRepeat := rs.SevenPairsRepeat
+
+	switch rs.Size() {
+	case 13:
+		ok, wait := IsSevenPairs(hand, allowRepeat)
+		if !ok {
+			return false, nil
+		}
+		return true, []mj.Tile{wait}
+	case 16:
+	default:
+		return false, nil
+	}
+
+	if len(hand) != 16 {
+		return false, nil
+	}
+
+	cnt := hand.ToCount()
+	for _, e := range cnt.Entries() {
+		if !e.Tile.CanMeld() {
+			return false, nil
+		}
+		m := cnt.Map()
+		m[e.Tile]++
+		if isLigu(m, allowRepeat) {
+			waits = append(waits, e.Tile)
+		}
+	}
+	sort.Sort(mj.Hand(waits))
+	return len(waits) > 0, waits
+}
+
+// isLigu returns true if the counts are seven pairs and one set of three identical tiles.
+func isLigu(m map[mj.Tile]int, allowRepeat bool) bool {
+	threes := 0
+	for _, n := range m {
+		switch {
+		case n == 2:
+		case n == 3:
+			threes++
+		case n == 4 && allowRepeat:
+		default:
+			return false
+		}
+	}
+	return threes == 1
+}
@@ -121,3 +121,67 @@ func TestIsSevenPairs(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSevenPairsWithRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		hand      mj.Hand
+		rs        mj.Ruleset
+		wantOk    bool
+		wantWaits []mj.Tile
+	}{
+		{
+			"13 tiles",
+			mj.MustParseHand("b1 b1 b2 b2 b3 b3 b4 b4 b5 b5 b6 b6 b7"),
+			mj.Ruleset{},
+			true,
+			mj.MustParseHand("b7"),
+		},
+		{
+			"ligu single",
+			mj.MustParseHand("b1 b1 b2 b2 b3 b3 b4 b4 b5 b5 b6 b6 hz hz hz b7"),
+			mj.Taiwanese,
+			true,
+			mj.MustParseHand("b7"),
+		},
+		{
+			"ligu double",
+			mj.MustParseHand("b1 b1 b2 b2 b3 b3 b4 b4 b5 b5 b6 b6 b7 b7 hz hz"),
+			mj.Taiwanese,
+			true,
+			mj.MustParseHand("b1 b2 b3 b4 b5 b6 b7 hz"),
+		},
+		{
+			"ligu no repeat",
+			mj.MustParseHand("b1 b1 b1 b1 b3 b3 b4 b4 b5 b5 b6 b6 hz hz hz b7"),
+			mj.Taiwanese,
+			false,
+			nil,
+		},
+		{
+			"not allowed",
+			mj.MustParseHand("b1 b1 b2 b2 b3 b3 b4 b4 b5 b5 b6 b6 b7"),
+			mj.Singapore,
+			false,
+			nil,
+		},
+		{
+			"ligu wrong",
+			mj.MustParseHand("b1 b2 b3 b4 b5 b6 c1 c2 c3 c4 c5 c6 w1 w2 w3 w4"),
+			mj.Taiwanese,
+			false,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOk, gotWaits := IsSevenPairsWithRules(tt.hand, tt.rs)
+			if gotOk != tt.wantOk {
+				t.Errorf("IsSevenPairsWithRules() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+			if !reflect.DeepEqual(gotWaits, tt.wantWaits) {
+				t.Errorf("IsSevenPairsWithRules() gotWaits = %v, want %v", gotWaits, tt.wantWaits)
+			}
+		})
+	}
+}
@@ -33,3 +33,13 @@ func IsThirteenOrphans(hand mj.Hand) (ok bool, wait mj.Tile) {
 	ok = true
 	return
 }
+
+// IsThirteenOrphansWithRules is like IsThirteenOrphans, but returns false unless the
+// Ruleset allows thirteen orphans and uses 13-tile hands, since thirteen orphans does
+// not exist in other hand sizes.
+func IsThirteenOrphansWithRules(hand mj.Hand, rs mj.Ruleset) (ok bool, wait mj.Tile) {
+	if rs.Size() != len(thirteenPure) || !rs.Allows(mj.ThirteenOrphansHand) {
+		return
+	}
+	return IsThirteenOrphans(hand)
+}
@@ -0,0 +1,52 @@
+package special
+
+import (
+	"sort"
+
+	"github.com/nik0sc/mj"
+)
+
+// Waits returns the tiles that would complete any of the special hands allowed by the
+// Ruleset, in sorted order without repeats. The hand should have as many tiles as
+// the Ruleset's waiting hand.
+func Waits(hand mj.Hand, rs mj.Ruleset) []mj.Tile {
+	found := make(map[mj.Tile]bool)
+
+	if ok, wait := IsThirteenOrphansWithRules(hand, rs); ok {
+		if wait.Valid() {
+			found[wait] = true
+		} else {
+			for _, t := range thirteenPure {
+				found[t] = true
+			}
+		}
+	}
+
+	if ok, waits := IsSevenPairsWithRules(hand, rs); ok {
+		for _, t := range waits {
+			found[t] = true
+		}
+	}
+
+	if rs.Allows(mj.HonoursAndKnittedHand) && len(hand) == 13 {
+		for _, t := range knittedCandidates {
+			if ok, _ := IsHonoursAndKnitted(hand.Append(t)); ok {
+				found[t] = true
+			}
+		}
+	}
+
+	waits := make(mj.Hand, 0, len(found))
+	for t := range found {
+		if rs.InPlay(t) {
+			waits = append(waits, t)
+		}
+	}
+	sort.Sort(waits)
+	return waits
+}
+
+// knittedCandidates holds every tile that can be part of an honours and knitted hand.
+var knittedCandidates = mj.MustParseHand(
+	"b1 b2 b3 b4 b5 b6 b7 b8 b9 c1 c2 c3 c4 c5 c6 c7 c8 c9 w1 w2 w3 w4 w5 w6 w7 w8 w9 " +
+		"he hs hw hn hz hf hb")
@@ -0,0 +1,60 @@
+package special
+
+import (
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestWaits(t *testing.T) {
+	tests := []struct {
+		name string
+		hand mj.Hand
+		rs   mj.Ruleset
+		want mj.Hand
+	}{
+		{
+			"thirteen orphans pure",
+			mj.MustParseHand("b1 b9 c1 c9 w1 w9 he hs hw hn hz hf hb"),
+			mj.HongKong,
+			mj.MustParseHand("b1 b9 c1 c9 w1 w9 he hs hw hn hz hf hb"),
+		},
+		{
+			"thirteen orphans sanma",
+			mj.MustParseHand("b1 b9 c1 c9 w1 w9 he hs hw hn hz hf hb"),
+			mj.Sanma,
+			mj.MustParseHand("b1 b9 c1 c9 w1 w9 he hs hw hn hz hf hb"),
+		},
+		{
+			"seven pairs",
+			mj.MustParseHand("b1 b1 b2 b2 b3 b3 b4 b4 b5 b5 b6 b6 b7"),
+			mj.HongKong,
+			mj.MustParseHand("b7"),
+		},
+		{
+			"seven pairs not allowed",
+			mj.MustParseHand("b1 b1 b2 b2 b3 b3 b4 b4 b5 b5 b6 b6 b7"),
+			mj.Singapore,
+			mj.Hand{},
+		},
+		{
+			"honours and knitted",
+			mj.MustParseHand("b1 b4 b7 c2 c5 w3 w6 w9 he hs hw hn hz"),
+			mj.MCR,
+			mj.MustParseHand("c8 hf hb"),
+		},
+		{
+			"honours and knitted not allowed",
+			mj.MustParseHand("b1 b4 b7 c2 c5 w3 w6 w9 he hs hw hn hz"),
+			mj.HongKong,
+			mj.Hand{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mj.Hand(Waits(tt.hand, tt.rs)); got.Marshal() != tt.want.Marshal() {
+				t.Errorf("Waits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package tenhou
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nik0sc/mj"
+)
+
+// jsonLog is the top level of a tenhou.net/6 log.
+type jsonLog struct {
+	Name []string            `json:"name"`
+	Log  [][]json.RawMessage `json:"log"`
+}
+
+// ParseJSON reads a log in the JSON format of the tenhou.net/6 viewer.
+//
+// Each round is an array of the round, repeat and riichi stick counts, the scores, the
+// dora and ura dora indicators, then for each seat the dealt hand, the tiles taken and
+// the tiles discarded, and lastly the result. A tile taken by a call is a string such as
+// "c275226" or "13p1313", where the letter comes before the called tile, and a discard
+// of 60 discards the tile just drawn. Discards may also be strings: "r" declares riichi,
+// "a" and "k" make closed and added kans, and "f" sets aside a North. The order of the
+// events is not written down, so it is worked out by following the turns from the
+// dealer and matching each call to the discard it claims.
+func ParseJSON(r io.Reader) (Log, error) {
+	var jl jsonLog
+	if err := json.NewDecoder(r).Decode(&jl); err != nil {
+		return Log{}, err
+	}
+	if len(jl.Log) == 0 {
+		return Log{}, fmt.Errorf("log has no rounds")
+	}
+
+	var log Log
+	for i, raw := range jl.Log {
+		rd, err := parseRound(raw)
+		if err != nil {
+			return Log{}, fmt.Errorf("round %d: %w", i, err)
+		}
+		log.Rounds = append(log.Rounds, rd)
+	}
+	n := len(log.Rounds[0].Hands)
+	log.Rules = rules(n)
+	log.Players = jl.Name
+	if len(log.Players) > n {
+		log.Players = log.Players[:n]
+	}
+	return log, nil
+}
+
+// item is a tile taken or discarded: either a tile code or a call.
+type item struct {
+	code int
+	call string
+}
+
+func (it *item) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		return json.Unmarshal(b, &it.call)
+	}
+	return json.Unmarshal(b, &it.code)
+}
+
+// seatLog is one seat's part of a round, and how far it has been followed.
+type seatLog struct {
+	takes, discards []item
+	take, discard   int
+}
+
+func (s *seatLog) nextTake() (item, bool) {
+	if s.take >= len(s.takes) {
+		return item{}, false
+	}
+	return s.takes[s.take], true
+}
+
+// parseRound reads one round and follows its turns to put its events in order.
+func parseRound(raw []json.RawMessage) (Round, error) {
+	if len(raw) < 5 {
+		return Round{}, fmt.Errorf("only %d fields", len(raw))
+	}
+	var (
+		rd     Round
+		counts []int
+		dora   []int
+	)
+	if err := json.Unmarshal(raw[0], &counts); err != nil || len(counts) < 2 {
+		return Round{}, fmt.Errorf("bad round counts %s", raw[0])
+	}
+	rd.Round, rd.Honba = counts[0], counts[1]
+	if err := json.Unmarshal(raw[2], &dora); err != nil {
+		return Round{}, fmt.Errorf("bad dora %s", raw[2])
+	}
+	for _, c := range dora {
+		t, _, err := CodeTile(c)
+		if err != nil {
+			return Round{}, err
+		}
+		rd.Dora = append(rd.Dora, t)
+	}
+
+	var seats []seatLog
+	for i := 4; i+2 < len(raw); i += 3 {
+		var hand []int
+		var s seatLog
+		if err := json.Unmarshal(raw[i], &hand); err != nil {
+			return Round{}, fmt.Errorf("bad hand %s", raw[i])
+		}
+		if len(hand) == 0 {
+			// the empty seat of a three-player game
+			continue
+		}
+		if err := json.Unmarshal(raw[i+1], &s.takes); err != nil {
+			return Round{}, fmt.Errorf("bad takes %s: %w", raw[i+1], err)
+		}
+		if err := json.Unmarshal(raw[i+2], &s.discards); err != nil {
+			return Round{}, fmt.Errorf("bad discards %s: %w", raw[i+2], err)
+		}
+		h := make(mj.Hand, len(hand))
+		for j, c := range hand {
+			t, _, err := CodeTile(c)
+			if err != nil {
+				return Round{}, err
+			}
+			h[j] = t
+		}
+		rd.Hands = append(rd.Hands, h)
+		seats = append(seats, s)
+	}
+	n := len(seats)
+	if n < 3 {
+		return Round{}, fmt.Errorf("%d seats", n)
+	}
+	rd.Rules = rules(n)
+	rd.Dealer = rd.Round % n
+
+	events, err := follow(seats, rd.Dealer)
+	if err != nil {
+		return Round{}, err
+	}
+	rd.Events = events
+	end, err := parseResult(raw[len(raw)-1], events)
+	if err != nil {
+		return Round{}, err
+	}
+	rd.Events = append(rd.Events, end...)
+	return rd, nil
+}
+
+// follow puts the takes and discards of every seat in order, starting with the dealer's
+// first draw.
+func follow(seats []seatLog, dealer int) ([]Event, error) {
+	n := len(seats)
+	var out []Event
+	cur, draw := dealer, true
+	var drawn Event
+	for {
+		s := &seats[cur]
+		if draw {
+			it, ok := s.nextTake()
+			if !ok {
+				return out, nil
+			}
+			if it.call != "" {
+				return nil, fmt.Errorf("seat %d: call %q where a draw was expected", cur, it.call)
+			}
+			s.take++
+			t, red, err := CodeTile(it.code)
+			if err != nil {
+				return nil, err
+			}
+			drawn = Event{Kind: Draw, Seat: cur, Tile: t, Red: red}
+			out = append(out, drawn)
+		}
+
+		if s.discard >= len(s.discards) {
+			// the seat won on its draw
+			return out, nil
+		}
+		it := s.discards[s.discard]
+		s.discard++
+		e, err := discardEvent(cur, it, drawn)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+		if e.Kind != Discard {
+			// a kan or nuki is followed by a replacement draw
+			draw = true
+			continue
+		}
+
+		claimer, call, err := findCall(seats, cur, e)
+		if err != nil {
+			return nil, err
+		}
+		if claimer < 0 {
+			cur, draw = (cur+1)%n, true
+			continue
+		}
+		seats[claimer].take++
+		out = append(out, call)
+		cur, draw = claimer, false
+		drawn = Event{}
+		if call.Meld.Kind == mj.Gang {
+			// an open kan takes the place of a discard
+			if c := &seats[claimer]; c.discard < len(c.discards) {
+				c.discard++
+			}
+			draw = true
+		}
+	}
+}
+
+// discardEvent reads a seat's discard, which may be a kan, a nuki or a riichi.
+func discardEvent(seat int, it item, drawn Event) (Event, error) {
+	e := Event{Kind: Discard, Seat: seat}
+	code := it.code
+	if it.call != "" {
+		letter, called, codes, err := parseCall(it.call)
+		if err != nil {
+			return Event{}, err
+		}
+		t, red, err := CodeTile(called)
+		if err != nil {
+			return Event{}, err
+		}
+		switch letter {
+		case 'r':
+			e.Riichi = true
+			code = called
+		case 'a':
+			if len(codes) != 4 {
+				return Event{}, fmt.Errorf("bad closed kan %q", it.call)
+			}
+			return Event{Kind: ClosedKan, Seat: seat, From: seat, Tile: t,
+				Meld: mj.Meld{Kind: mj.Gang, Tile: t, Concealed: true}}, nil
+		case 'k':
+			return Event{Kind: AddedKan, Seat: seat, From: seat, Tile: t, Red: red,
+				Meld: mj.Meld{Kind: mj.Gang, Tile: t}}, nil
+		case 'f':
+			return Event{Kind: Nuki, Seat: seat, From: seat, Tile: t, Red: red}, nil
+		default:
+			return Event{}, fmt.Errorf("unknown discard %q", it.call)
+		}
+	}
+	if code == 60 {
+		e.Tile, e.Red = drawn.Tile, drawn.Red
+		if drawn.Kind != Draw {
+			return Event{}, fmt.Errorf("seat %d discarded its draw after a call", seat)
+		}
+		return e, nil
+	}
+	var err error
+	e.Tile, e.Red, err = CodeTile(code)
+	return e, err
+}
+
+// findCall returns the seat whose next take calls the discard, or -1. A pon or kan by
+// any seat takes priority over a chi by the next seat.
+func findCall(seats []seatLog, from int, d Event) (int, Event, error) {
+	n := len(seats)
+	claimer, rank := -1, 0
+	var call Event
+	for i := 1; i < n; i++ {
+		seat := (from + i) % n
+		it, ok := seats[seat].nextTake()
+		if !ok || it.call == "" {
+			continue
+		}
+		letter, called, codes, err := parseCall(it.call)
+		if err != nil {
+			return 0, Event{}, err
+		}
+		t, red, err := CodeTile(called)
+		if err != nil {
+			return 0, Event{}, err
+		}
+		if t != d.Tile {
+			continue
+		}
+		e := Event{Kind: Call, Seat: seat, From: from, Tile: t, Red: red}
+		r := 2
+		switch letter {
+		case 'c':
+			if i != 1 || len(codes) != 3 {
+				continue
+			}
+			low := t
+			for _, c := range codes {
+				if ct, _, err := CodeTile(c); err == nil && ct.Suit == low.Suit && ct.Value < low.Value {
+					low = ct
+				}
+			}
+			e.Meld, r = mj.Meld{Kind: mj.Chi, Tile: low}, 1
+		case 'p':
+			e.Meld = mj.Meld{Kind: mj.Peng, Tile: t}
+		case 'm':
+			e.Meld = mj.Meld{Kind: mj.Gang, Tile: t}
+		default:
+			continue
+		}
+		if r > rank {
+			claimer, call, rank = seat, e, r
+		}
+	}
+	return claimer, call, nil
+}
+
+// parseCall reads a call such as "13p1313", and returns its letter, the code after the
+// letter, and every code in it.
+func parseCall(s string) (letter byte, called int, codes []int, err error) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < '0' || c > '9' {
+			if letter != 0 {
+				return 0, 0, nil, fmt.Errorf("bad call %q", s)
+			}
+			letter = c
+			i++
+			if i+2 > len(s) {
+				return 0, 0, nil, fmt.Errorf("bad call %q", s)
+			}
+			called = int(s[i]-'0')*10 + int(s[i+1]-'0')
+			continue
+		}
+		if i+2 > len(s) || s[i+1] < '0' || s[i+1] > '9' {
+			return 0, 0, nil, fmt.Errorf("bad call %q", s)
+		}
+		codes = append(codes, int(c-'0')*10+int(s[i+1]-'0'))
+		i += 2
+	}
+	if letter == 0 {
+		return 0, 0, nil, fmt.Errorf("bad call %q", s)
+	}
+	return letter, called, codes, nil
+}
+
+// parseResult reads the last field of a round: the name of the result, then for each
+// win its score changes and an array beginning with the winner and the seat it won from.
+func parseResult(raw json.RawMessage, events []Event) ([]Event, error) {
+	var res []json.RawMessage
+	if err := json.Unmarshal(raw, &res); err != nil || len(res) == 0 {
+		return nil, fmt.Errorf("bad result %s", raw)
+	}
+	var name string
+	if err := json.Unmarshal(res[0], &name); err != nil {
+		return nil, fmt.Errorf("bad result %s", raw)
+	}
+	if !strings.Contains(name, "和了") {
+		return []Event{{Kind: Exhausted}}, nil
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("win without any events")
+	}
+	last := events[len(events)-1]
+	if last.Kind == Call {
+		return nil, fmt.Errorf("win on a call")
+	}
+
+	var out []Event
+	for i := 2; i < len(res); i += 2 {
+		var info []json.RawMessage
+		var who, from int
+		if err := json.Unmarshal(res[i], &info); err != nil || len(info) < 2 {
+			return nil, fmt.Errorf("bad win %s", res[i])
+		}
+		if json.Unmarshal(info[0], &who) != nil || json.Unmarshal(info[1], &from) != nil {
+			return nil, fmt.Errorf("bad win %s", res[i])
+		}
+		out = append(out, Event{Kind: Win, Seat: who, From: from, Tile: last.Tile, Red: last.Red})
+	}
+	return out, nil
+}
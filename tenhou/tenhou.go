@@ -0,0 +1,180 @@
+// Package tenhou imports game logs from Tenhou, so that the analysers in this module can
+// be run over real games.
+//
+// Two formats are read: the mjlog XML that Tenhou serves for its replays, where each tile
+// is an index from 0 to 135, and the JSON of the tenhou.net/6 log viewer, where each tile
+// is a 2-digit code such as 15 for the 5 of characters or 51 for its red copy. Both are
+// turned into a Log of rounds, each holding the dealt hands and the events of the round in
+// order. Round.Turns replays a round to give each seat's hand at every discard.
+//
+// The characters, dots and bamboo suits are mj.Wan, mj.Coin and mj.Bamboo. Red fives are
+// not distinct Tiles, so each event notes whether its tile is red.
+package tenhou
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/nik0sc/mj"
+)
+
+// ErrInconsistent is returned when a round's events could not have happened, such as a
+// discard of a tile that is not in the hand.
+var ErrInconsistent = errors.New("inconsistent log")
+
+// Log is an imported game.
+type Log struct {
+	// Players holds the name of the player in each seat.
+	Players []string
+	// Rules is mj.Riichi, or mj.Sanma for three-player games.
+	Rules  mj.Ruleset
+	Rounds []Round
+}
+
+// Round is one hand of a game, from the deal until it is won or drawn.
+type Round struct {
+	Rules mj.Ruleset
+	// Round counts the rounds of the game, without repeats: 0 is East 1, 1 is East 2, 4
+	// is South 1 and so on.
+	Round int
+	// Honba is the number of repeat counters.
+	Honba int
+	// Dealer is the seat of the dealer.
+	Dealer int
+	// Hands holds the tiles dealt to each seat, sorted.
+	Hands []mj.Hand
+	// Dora holds the dora indicators in the order they were revealed.
+	Dora mj.Hand
+	// Events holds what happened in the round, in order.
+	Events []Event
+}
+
+// EventKind is the kind of an Event.
+type EventKind int
+
+const (
+	// Draw is a tile drawn from the wall, including replacement tiles after a kan.
+	Draw EventKind = iota + 1
+	// Discard is a discard, which may declare riichi.
+	Discard
+	// Call is a chi, pon or open kan made by claiming a discard.
+	Call
+	// ClosedKan is a kan made of four tiles from the hand.
+	ClosedKan
+	// AddedKan adds a tile from the hand to a pon, making it a kan.
+	AddedKan
+	// Nuki sets aside a North as a bonus tile in three-player games.
+	Nuki
+	// Win is a win. There may be more than one Win at the end of a round.
+	Win
+	// Exhausted ends a round that nobody won, including rounds that were abandoned.
+	Exhausted
+)
+
+// String returns the name of the EventKind.
+func (k EventKind) String() string {
+	switch k {
+	case Draw:
+		return "draw"
+	case Discard:
+		return "discard"
+	case Call:
+		return "call"
+	case ClosedKan:
+		return "closed kan"
+	case AddedKan:
+		return "added kan"
+	case Nuki:
+		return "nuki"
+	case Win:
+		return "win"
+	case Exhausted:
+		return "exhausted"
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// Event is something that happened in a Round.
+type Event struct {
+	Kind EventKind
+	Seat int
+	// Tile is the tile that was drawn, discarded, claimed, added to a pon, set aside or
+	// won on. For a closed kan, it is the tile of the kan.
+	Tile mj.Tile
+	// Red is true if Tile is a red five.
+	Red bool
+	// From is the seat that discarded the claimed or winning tile. For a win on a drawn
+	// tile, it is Seat.
+	From int
+	// Meld is the meld formed by a Call, ClosedKan or AddedKan.
+	Meld mj.Meld
+	// Riichi is true if a Discard declares riichi.
+	Riichi bool
+}
+
+// Read reads a log in either format, telling them apart by their first character.
+func Read(r io.Reader) (Log, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Log{}, err
+	}
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '<' {
+		return ParseXML(bytes.NewReader(b))
+	}
+	return ParseJSON(bytes.NewReader(b))
+}
+
+// Tile returns the tile for an mjlog index from 0 to 135, and whether it is a red five.
+// Each group of four indices is one kind of tile, in the order 1-9 of characters, 1-9 of
+// dots, 1-9 of bamboo, then East, South, West, North, white, green and red dragons. The
+// red fives are 16, 52 and 88, the first copy of each five.
+func Tile(i int) (t mj.Tile, red bool, err error) {
+	if i < 0 || i >= 136 {
+		return mj.Tile{}, false, fmt.Errorf("tile index %d out of range", i)
+	}
+	t = kind(i / 4)
+	return t, i == 16 || i == 52 || i == 88, nil
+}
+
+// CodeTile returns the tile for a tenhou.net/6 code, and whether it is a red five. The
+// tens digit is the suit: 1 for characters, 2 for dots, 3 for bamboo and 4 for honours,
+// in the same order as for Tile. The red fives are 51, 52 and 53.
+func CodeTile(c int) (t mj.Tile, red bool, err error) {
+	switch {
+	case c >= 51 && c <= 53:
+		return kind((c-51)*9 + 4), true, nil
+	case c%10 == 0 || c < 11 || c > 47:
+	case c/10 < 4:
+		return kind((c/10-1)*9 + c%10 - 1), false, nil
+	case c%10 <= 7:
+		return kind(27 + c%10 - 1), false, nil
+	}
+	return mj.Tile{}, false, fmt.Errorf("bad tile code %d", c)
+}
+
+var honours = [7]mj.Value{mj.East, mj.South, mj.West, mj.North, mj.Ban, mj.Fa, mj.Zhong}
+
+// kind returns the tile for a kind from 0 to 33.
+func kind(k int) mj.Tile {
+	switch {
+	case k < 9:
+		return mj.Tile{Suit: mj.Wan, Value: mj.Value(k + 1)}
+	case k < 18:
+		return mj.Tile{Suit: mj.Coin, Value: mj.Value(k - 8)}
+	case k < 27:
+		return mj.Tile{Suit: mj.Bamboo, Value: mj.Value(k - 17)}
+	}
+	return mj.Tile{Suit: mj.Honour, Value: honours[k-27]}
+}
+
+// rules returns the Ruleset for a game with the number of players.
+func rules(players int) mj.Ruleset {
+	if players == 3 {
+		return mj.Sanma
+	}
+	return mj.Riichi
+}
@@ -0,0 +1,208 @@
+package tenhou
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestTile(t *testing.T) {
+	for _, c := range []struct {
+		i    int
+		want string
+		red  bool
+	}{
+		{0, "w1", false},
+		{16, "w5", true},
+		{17, "w5", false},
+		{36, "c1", false},
+		{52, "c5", true},
+		{88, "b5", true},
+		{107, "b9", false},
+		{108, "he", false},
+		{123, "hn", false},
+		{124, "hb", false},
+		{128, "hf", false},
+		{135, "hz", false},
+	} {
+		got, red, err := Tile(c.i)
+		if err != nil || mj.FormatTile(got) != c.want || red != c.red {
+			t.Errorf("Tile(%d) = %v, %v, %v, want %s, %v", c.i, got, red, err, c.want, c.red)
+		}
+	}
+	for _, i := range []int{-1, 136} {
+		if _, _, err := Tile(i); err == nil {
+			t.Errorf("Tile(%d) should fail", i)
+		}
+	}
+}
+
+func TestCodeTile(t *testing.T) {
+	for _, c := range []struct {
+		code int
+		want string
+		red  bool
+	}{
+		{11, "w1", false},
+		{19, "w9", false},
+		{25, "c5", false},
+		{39, "b9", false},
+		{41, "he", false},
+		{44, "hn", false},
+		{45, "hb", false},
+		{47, "hz", false},
+		{51, "w5", true},
+		{52, "c5", true},
+		{53, "b5", true},
+	} {
+		got, red, err := CodeTile(c.code)
+		if err != nil || mj.FormatTile(got) != c.want || red != c.red {
+			t.Errorf("CodeTile(%d) = %v, %v, %v, want %s, %v", c.code, got, red, err, c.want, c.red)
+		}
+	}
+	for _, c := range []int{0, 10, 20, 48, 54, 60} {
+		if _, _, err := CodeTile(c); err == nil {
+			t.Errorf("CodeTile(%d) should fail", c)
+		}
+	}
+}
+
+func TestDecodeMeld(t *testing.T) {
+	for _, c := range []struct {
+		who, m int
+		want   Event
+	}{
+		// seat 2 pons the red dragon at index 133 from seat 0
+		{2, 51306, Event{Kind: Call, Seat: 2, From: 0, Tile: mj.MustParseHand("hz")[0],
+			Meld: mj.Meld{Kind: mj.Peng, Tile: mj.MustParseHand("hz")[0]}}},
+		// seat 1 chis 4-5-6 of characters, calling the 5 from seat 0
+		{1, 10279, Event{Kind: Call, Seat: 1, From: 0, Tile: mj.MustParseHand("w5")[0],
+			Meld: mj.Meld{Kind: mj.Chi, Tile: mj.MustParseHand("w4")[0]}}},
+		// seat 3 makes a closed kan of West
+		{3, 116 << 8, Event{Kind: ClosedKan, Seat: 3, From: 3, Tile: mj.MustParseHand("hw")[0],
+			Meld: mj.Meld{Kind: mj.Gang, Tile: mj.MustParseHand("hw")[0], Concealed: true}}},
+		// seat 0 calls an open kan on the red 5 of dots from seat 1
+		{0, 52<<8 | 1, Event{Kind: Call, Seat: 0, From: 1, Tile: mj.MustParseHand("c5")[0], Red: true,
+			Meld: mj.Meld{Kind: mj.Gang, Tile: mj.MustParseHand("c5")[0]}}},
+		// seat 1 adds the 1 of bamboo at index 72 to a pon
+		{1, (18*3)<<9 | 0<<5 | 0x10 | 2, Event{Kind: AddedKan, Seat: 1, From: 1, Tile: mj.MustParseHand("b1")[0],
+			Meld: mj.Meld{Kind: mj.Gang, Tile: mj.MustParseHand("b1")[0]}}},
+	} {
+		got, err := decodeMeld(c.who, c.m, 4)
+		if err != nil || got != c.want {
+			t.Errorf("decodeMeld(%d, %d) = %+v, %v, want %+v", c.who, c.m, got, err, c.want)
+		}
+	}
+}
+
+func readSample(t *testing.T, name string) Log {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	l, err := Read(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestRead(t *testing.T) {
+	x := readSample(t, "sample.mjlog")
+	j := readSample(t, "sample.json")
+	if !reflect.DeepEqual(x, j) {
+		t.Errorf("mjlog and json differ:\n%+v\n%+v", x, j)
+	}
+
+	if !reflect.DeepEqual(x.Players, []string{"A", "B", "C", "D"}) || x.Rules.Name != mj.Riichi.Name {
+		t.Errorf("unexpected log %+v", x)
+	}
+	if len(x.Rounds) != 1 {
+		t.Fatalf("%d rounds, want 1", len(x.Rounds))
+	}
+	rd := x.Rounds[0]
+	if rd.Dealer != 0 || mj.FormatHand(rd.Dora) != "w8" || len(rd.Hands) != 4 {
+		t.Errorf("unexpected round %+v", rd)
+	}
+	var kinds []EventKind
+	for _, e := range rd.Events {
+		kinds = append(kinds, e.Kind)
+	}
+	want := []EventKind{Draw, Discard, Call, Discard, Draw, Discard, Draw, Discard, Call, Discard, Draw, Discard, Win}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("events %v, want %v", kinds, want)
+	}
+	if w := rd.Events[len(rd.Events)-1]; w.Seat != 0 || w.From != 2 || mj.FormatTile(w.Tile) != "he" {
+		t.Errorf("win %+v", w)
+	}
+}
+
+func TestRound_Turns(t *testing.T) {
+	rd := readSample(t, "sample.mjlog").Rounds[0]
+	turns, err := rd.Turns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seats []int
+	var discards []string
+	for _, tn := range turns {
+		seats = append(seats, tn.Seat)
+		discards = append(discards, mj.FormatTile(tn.Discard.Tile))
+		if len(tn.Hand)%3 != 2 {
+			t.Errorf("seat %d discarded from %d tiles", tn.Seat, len(tn.Hand))
+		}
+	}
+	if !reflect.DeepEqual(seats, []int{0, 2, 3, 0, 1, 2}) ||
+		!reflect.DeepEqual(discards, []string{"hz", "hb", "hf", "w5", "hw", "he"}) {
+		t.Errorf("turns by %v discarding %v", seats, discards)
+	}
+
+	pon := turns[1]
+	if len(pon.Hand) != 11 || pon.Drawn.Valid() || len(pon.Melds[2]) != 1 || pon.Melds[2][0].Kind != mj.Peng {
+		t.Errorf("turn after pon: %+v", pon)
+	}
+	if len(pon.Group.Chis) != 3 {
+		t.Errorf("group %+v should hold the three runs in the hand", pon.Group)
+	}
+	found := false
+	for _, p := range pon.Group.Pengs {
+		found = found || mj.FormatTile(p) == "hz"
+	}
+	if !found {
+		t.Errorf("group %+v does not hold the called pon", pon.Group)
+	}
+
+	riichi := turns[3]
+	if !riichi.Discard.Riichi || riichi.Riichi[0] || mj.FormatTile(riichi.Drawn) != "hn" {
+		t.Errorf("riichi turn %+v", riichi)
+	}
+	if last := turns[5]; !last.Riichi[0] || len(last.Discards[0]) != 2 || len(last.Melds[1]) != 1 {
+		t.Errorf("last turn %+v", last)
+	}
+}
+
+func TestRound_TurnsInconsistent(t *testing.T) {
+	rd := readSample(t, "sample.json").Rounds[0]
+	bad := func(f func(es []Event)) {
+		t.Helper()
+		r := rd
+		r.Events = append([]Event(nil), rd.Events...)
+		f(r.Events)
+		if _, err := r.Turns(); !errors.Is(err, ErrInconsistent) {
+			t.Errorf("Turns() = %v, want ErrInconsistent", err)
+		}
+	}
+	// discard a tile that is not in the hand
+	bad(func(es []Event) { es[1].Tile = mj.MustParseHand("c9")[0] })
+	// pon a tile other than the discard
+	bad(func(es []Event) { es[2].Tile = mj.MustParseHand("hb")[0] })
+	// win on an incomplete hand
+	bad(func(es []Event) { es[len(es)-1].Seat = 3 })
+	// draw twice
+	bad(func(es []Event) { es[1] = es[0] })
+}
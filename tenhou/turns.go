@@ -0,0 +1,236 @@
+package tenhou
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+)
+
+// Turn is a seat's decision to discard, with the table as it was just before the discard.
+type Turn struct {
+	Seat int
+	// Hand holds the seat's concealed tiles, sorted, including the tile drawn or claimed
+	// this turn.
+	Hand mj.Hand
+	// Group is the best grouping of Hand found by handcheck.Solver, with the seat's melds
+	// added to it.
+	Group mj.Group
+	// Drawn is the tile drawn this turn, or the zero Tile if the seat called a discard.
+	Drawn mj.Tile
+	// Melds holds each seat's melds.
+	Melds [][]mj.Meld
+	// Discards holds each seat's discards before this turn, including discards that were
+	// claimed.
+	Discards []mj.Hand
+	// Riichi is true for each seat that has declared riichi.
+	Riichi []bool
+	// Discard is the discard that ends the turn.
+	Discard Event
+}
+
+// table is the state of a round being replayed.
+type table struct {
+	hands    []mj.Hand
+	solvers  []handcheck.Solver
+	melds    [][]mj.Meld
+	discards []mj.Hand
+	riichi   []bool
+	drawn    mj.Tile
+	last     *Event
+}
+
+// Turns replays the round and returns every discard in order, with the hand it was made
+// from. It returns an error wrapping ErrInconsistent if a tile is discarded or melded
+// that is not in the hand, a discard is claimed after the next draw, or a winning hand
+// is not complete.
+func (r Round) Turns() ([]Turn, error) {
+	n := len(r.Hands)
+	t := &table{
+		hands:    make([]mj.Hand, n),
+		solvers:  make([]handcheck.Solver, n),
+		melds:    make([][]mj.Meld, n),
+		discards: make([]mj.Hand, n),
+		riichi:   make([]bool, n),
+	}
+	for s, h := range r.Hands {
+		t.hands[s] = append(mj.Hand(nil), h...)
+		sort.Sort(t.hands[s])
+		t.solvers[s].NoChi = r.Rules.NoChi
+		t.solvers[s].Set(t.hands[s])
+	}
+
+	var out []Turn
+	for i := range r.Events {
+		e := r.Events[i]
+		if e.Kind != Exhausted && (e.Seat < 0 || e.Seat >= n) {
+			return nil, fmt.Errorf("event %d: %w: no seat %d", i, ErrInconsistent, e.Seat)
+		}
+		if e.Kind == Discard {
+			if err := t.size(e.Seat, 2); err != nil {
+				return nil, fmt.Errorf("event %d (%s): %w", i, e.Kind, err)
+			}
+			out = append(out, t.turn(e))
+		}
+		if err := t.apply(e, r.Rules); err != nil {
+			return nil, fmt.Errorf("event %d (%s): %w", i, e.Kind, err)
+		}
+		// a win may be on the latest discard, or on a tile added to a pon
+		switch e.Kind {
+		case Discard, AddedKan:
+			t.last = &r.Events[i]
+		case Win:
+		default:
+			t.last = nil
+		}
+	}
+	return out, nil
+}
+
+// turn returns the Turn that ends with the discard.
+func (t *table) turn(e Event) Turn {
+	s := e.Seat
+	g := t.solvers[s].Group()
+	for _, m := range t.melds[s] {
+		if m.Kind == mj.Chi {
+			g.Chis = append(g.Chis, m.Tile)
+		} else {
+			g.Pengs = append(g.Pengs, m.Tile)
+		}
+	}
+	turn := Turn{
+		Seat:     s,
+		Hand:     append(mj.Hand(nil), t.hands[s]...),
+		Group:    g,
+		Drawn:    t.drawn,
+		Melds:    make([][]mj.Meld, len(t.melds)),
+		Discards: make([]mj.Hand, len(t.discards)),
+		Riichi:   append([]bool(nil), t.riichi...),
+		Discard:  e,
+	}
+	for i := range t.melds {
+		turn.Melds[i] = append([]mj.Meld(nil), t.melds[i]...)
+		turn.Discards[i] = append(mj.Hand(nil), t.discards[i]...)
+	}
+	return turn
+}
+
+func (t *table) apply(e Event, rs mj.Ruleset) error {
+	s := e.Seat
+	switch e.Kind {
+	case Draw:
+		if err := t.size(s, 1); err != nil {
+			return err
+		}
+		t.add(s, e.Tile)
+		t.drawn = e.Tile
+	case Discard:
+		if !t.take(s, e.Tile) {
+			return fmt.Errorf("%w: seat %d discarded %s, which it does not have", ErrInconsistent, s, mj.FormatTile(e.Tile))
+		}
+		t.discards[s] = append(t.discards[s], e.Tile)
+		t.riichi[s] = t.riichi[s] || e.Riichi
+	case Call:
+		if t.last == nil || t.last.Kind != Discard || t.last.Seat != e.From || t.last.Tile != e.Tile || e.From == s {
+			return fmt.Errorf("%w: seat %d called %s from seat %d, which is not the latest discard",
+				ErrInconsistent, s, mj.FormatTile(e.Tile), e.From)
+		}
+		if err := t.size(s, 1); err != nil {
+			return err
+		}
+		if !e.Meld.Valid() || e.Meld.Concealed || !e.Meld.Contains(e.Tile) {
+			return fmt.Errorf("%w: cannot call %s to make %s", ErrInconsistent, mj.FormatTile(e.Tile), e.Meld)
+		}
+		claimed := e.Tile
+		for _, mt := range e.Meld.Tiles() {
+			if mt == claimed {
+				claimed = mj.Tile{}
+			} else if !t.take(s, mt) {
+				return fmt.Errorf("%w: seat %d called %s without %s", ErrInconsistent, s, e.Meld, mj.FormatTile(mt))
+			}
+		}
+		t.melds[s] = append(t.melds[s], e.Meld)
+		t.drawn = mj.Tile{}
+	case ClosedKan:
+		if err := t.size(s, 2); err != nil {
+			return err
+		}
+		for _, mt := range e.Meld.Tiles() {
+			if !t.take(s, mt) {
+				return fmt.Errorf("%w: seat %d made %s without %s", ErrInconsistent, s, e.Meld, mj.FormatTile(mt))
+			}
+		}
+		t.melds[s] = append(t.melds[s], e.Meld)
+	case AddedKan:
+		if err := t.size(s, 2); err != nil {
+			return err
+		}
+		pon := -1
+		for i, m := range t.melds[s] {
+			if m.Kind == mj.Peng && m.Tile == e.Tile {
+				pon = i
+			}
+		}
+		if pon < 0 || !t.take(s, e.Tile) {
+			return fmt.Errorf("%w: seat %d cannot add %s to a pon", ErrInconsistent, s, mj.FormatTile(e.Tile))
+		}
+		t.melds[s][pon].Kind = mj.Gang
+	case Nuki:
+		if err := t.size(s, 2); err != nil {
+			return err
+		}
+		if !t.take(s, e.Tile) {
+			return fmt.Errorf("%w: seat %d set aside %s, which it does not have", ErrInconsistent, s, mj.FormatTile(e.Tile))
+		}
+	case Win:
+		hand := t.hands[s]
+		if e.From == s {
+			if err := t.size(s, 2); err != nil {
+				return err
+			}
+		} else {
+			if t.last == nil || t.last.Seat != e.From || t.last.Tile != e.Tile {
+				return fmt.Errorf("%w: seat %d won on %s from seat %d, which is not the latest discard",
+					ErrInconsistent, s, mj.FormatTile(e.Tile), e.From)
+			}
+			if err := t.size(s, 1); err != nil {
+				return err
+			}
+			hand = hand.Append(e.Tile)
+		}
+		if handcheck.Shanten(hand, rs) != -1 {
+			return fmt.Errorf("%w: seat %d won with %s, which is not complete", ErrInconsistent, s, mj.FormatHand(hand))
+		}
+	case Exhausted:
+	default:
+		return fmt.Errorf("%w: unknown event %s", ErrInconsistent, e.Kind)
+	}
+	return nil
+}
+
+// size checks that the seat's hand has 3n+rem tiles.
+func (t *table) size(s, rem int) error {
+	if len(t.hands[s])%3 != rem {
+		return fmt.Errorf("%w: seat %d has %d tiles", ErrInconsistent, s, len(t.hands[s]))
+	}
+	return nil
+}
+
+func (t *table) add(s int, tile mj.Tile) {
+	t.hands[s] = t.hands[s].Append(tile)
+	sort.Sort(t.hands[s])
+	t.solvers[s].Add(tile)
+}
+
+// take removes a tile from the seat's hand, and returns false if it is not there.
+func (t *table) take(s int, tile mj.Tile) bool {
+	for i, ht := range t.hands[s] {
+		if ht == tile {
+			t.hands[s] = t.hands[s].Remove(i)
+			t.solvers[s].Remove(tile)
+			return true
+		}
+	}
+	return false
+}
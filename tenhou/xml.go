@@ -0,0 +1,298 @@
+package tenhou
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nik0sc/mj"
+)
+
+// ParseXML reads an mjlog, the XML log that Tenhou serves for its replays.
+//
+// Each round begins with an INIT element holding the dealt hands. Draws and discards are
+// elements named by the seat and the tile index, such as T45 for seat 0 drawing index 45
+// and E12 for seat 1 discarding index 12. N elements hold calls, with the meld packed
+// into the m attribute, and AGARI and RYUUKYOKU elements end the round.
+func ParseXML(r io.Reader) (Log, error) {
+	var (
+		log     Log
+		round   *Round
+		riichi  = make(map[int]bool)
+		players = 4
+	)
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return Log{}, err
+		}
+		el, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		attr := func(name string) string {
+			for _, a := range el.Attr {
+				if a.Name.Local == name {
+					return a.Value
+				}
+			}
+			return ""
+		}
+		num := func(name string) (int, error) {
+			n, err := strconv.Atoi(attr(name))
+			if err != nil {
+				return 0, fmt.Errorf("%s: attribute %s: %w", el.Name.Local, name, err)
+			}
+			return n, nil
+		}
+
+		name := el.Name.Local
+		switch name {
+		case "GO":
+			if t, err := num("type"); err == nil && t&0x10 != 0 {
+				players = 3
+			}
+			continue
+		case "UN":
+			if log.Players != nil {
+				// a player reconnected
+				continue
+			}
+			for s := 0; s < 4; s++ {
+				n, err := url.QueryUnescape(attr(fmt.Sprintf("n%d", s)))
+				if err != nil {
+					return Log{}, fmt.Errorf("UN: %w", err)
+				}
+				log.Players = append(log.Players, n)
+			}
+			continue
+		case "INIT":
+			rd, err := parseInit(attr, players)
+			if err != nil {
+				return Log{}, err
+			}
+			log.Rounds = append(log.Rounds, rd)
+			round = &log.Rounds[len(log.Rounds)-1]
+			riichi = make(map[int]bool)
+			continue
+		}
+		if round == nil {
+			continue
+		}
+
+		var e Event
+		switch name {
+		case "N":
+			who, err := num("who")
+			if err != nil {
+				return Log{}, err
+			}
+			m, err := num("m")
+			if err != nil {
+				return Log{}, err
+			}
+			if e, err = decodeMeld(who, m, len(round.Hands)); err != nil {
+				return Log{}, err
+			}
+		case "REACH":
+			who, err := num("who")
+			if err != nil {
+				return Log{}, err
+			}
+			if attr("step") == "1" {
+				riichi[who] = true
+			}
+			continue
+		case "DORA":
+			hai, err := num("hai")
+			if err != nil {
+				return Log{}, err
+			}
+			t, _, err := Tile(hai)
+			if err != nil {
+				return Log{}, err
+			}
+			round.Dora = append(round.Dora, t)
+			continue
+		case "AGARI":
+			var err error
+			e.Kind = Win
+			if e.Seat, err = num("who"); err != nil {
+				return Log{}, err
+			}
+			if e.From, err = num("fromWho"); err != nil {
+				return Log{}, err
+			}
+			machi, err := num("machi")
+			if err != nil {
+				return Log{}, err
+			}
+			if e.Tile, e.Red, err = Tile(machi); err != nil {
+				return Log{}, err
+			}
+		case "RYUUKYOKU":
+			e.Kind = Exhausted
+		default:
+			seat, kind, i, ok := drawOrDiscard(name)
+			if !ok {
+				continue
+			}
+			var err error
+			if e.Tile, e.Red, err = Tile(i); err != nil {
+				return Log{}, err
+			}
+			e.Kind, e.Seat = kind, seat
+			if kind == Discard && riichi[seat] {
+				e.Riichi = true
+				delete(riichi, seat)
+			}
+		}
+		round.Events = append(round.Events, e)
+	}
+
+	if len(log.Rounds) == 0 {
+		return Log{}, fmt.Errorf("mjlog has no rounds")
+	}
+	n := len(log.Rounds[0].Hands)
+	log.Rules = rules(n)
+	if len(log.Players) > n {
+		log.Players = log.Players[:n]
+	}
+	return log, nil
+}
+
+// parseInit reads the INIT element that begins a round.
+func parseInit(attr func(string) string, players int) (Round, error) {
+	seed := strings.Split(attr("seed"), ",")
+	if len(seed) < 6 {
+		return Round{}, fmt.Errorf("INIT: bad seed %q", attr("seed"))
+	}
+	var rd Round
+	var vals [6]int
+	for i := range vals {
+		v, err := strconv.Atoi(seed[i])
+		if err != nil {
+			return Round{}, fmt.Errorf("INIT: bad seed %q", attr("seed"))
+		}
+		vals[i] = v
+	}
+	rd.Round, rd.Honba = vals[0], vals[1]
+	t, _, err := Tile(vals[5])
+	if err != nil {
+		return Round{}, fmt.Errorf("INIT: %w", err)
+	}
+	rd.Dora = mj.Hand{t}
+	if rd.Dealer, err = strconv.Atoi(attr("oya")); err != nil {
+		return Round{}, fmt.Errorf("INIT: bad oya %q", attr("oya"))
+	}
+
+	for s := 0; s < 4; s++ {
+		hai := attr(fmt.Sprintf("hai%d", s))
+		if hai == "" {
+			if s < players {
+				return Round{}, fmt.Errorf("INIT: no hand for seat %d", s)
+			}
+			break
+		}
+		var h mj.Hand
+		for _, f := range strings.Split(hai, ",") {
+			i, err := strconv.Atoi(f)
+			if err != nil {
+				return Round{}, fmt.Errorf("INIT: bad hand %q", hai)
+			}
+			t, _, err := Tile(i)
+			if err != nil {
+				return Round{}, fmt.Errorf("INIT: %w", err)
+			}
+			h = append(h, t)
+		}
+		rd.Hands = append(rd.Hands, h)
+	}
+	rd.Rules = rules(len(rd.Hands))
+	return rd, nil
+}
+
+// drawOrDiscard reads an element name such as T45 or E12.
+func drawOrDiscard(name string) (seat int, kind EventKind, i int, ok bool) {
+	if len(name) < 2 {
+		return 0, 0, 0, false
+	}
+	i, err := strconv.Atoi(name[1:])
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	if s := strings.IndexByte("TUVW", name[0]); s >= 0 {
+		return s, Draw, i, true
+	}
+	if s := strings.IndexByte("DEFG", name[0]); s >= 0 {
+		return s, Discard, i, true
+	}
+	return 0, 0, 0, false
+}
+
+// decodeMeld unpacks the m attribute of an N element. The lowest 2 bits give the seat
+// that the tile was called from, counting onwards from the caller, and the next bits say
+// which kind of meld it is. The rest give the tiles, in a layout for each kind.
+func decodeMeld(who, m, players int) (Event, error) {
+	e := Event{Kind: Call, Seat: who, From: (who + m&3) % players}
+	red := func(i int) bool { return i == 16 || i == 52 || i == 88 }
+
+	switch {
+	case m&0x4 != 0:
+		// chi: the lowest tile kind and which tile was called, then the copy of each
+		t := m >> 10
+		called := t % 3
+		t /= 3
+		base := t/7*9 + t%7
+		if base+2 >= 27 {
+			return Event{}, fmt.Errorf("bad chi %d", m)
+		}
+		e.Meld = mj.Meld{Kind: mj.Chi, Tile: kind(base)}
+		e.Tile = kind(base + called)
+		e.Red = red((base+called)*4 + m>>(3+2*called)&3)
+	case m&0x18 != 0:
+		// pon or added kan: the tile kind and which tile was called, and the copy that
+		// is not in the pon
+		t := m >> 9
+		called := t % 3
+		t /= 3
+		unused := m >> 5 & 3
+		if t >= 34 {
+			return Event{}, fmt.Errorf("bad pon %d", m)
+		}
+		if m&0x8 != 0 {
+			var copies []int
+			for c := 0; c < 4; c++ {
+				if c != unused {
+					copies = append(copies, c)
+				}
+			}
+			e.Meld = mj.Meld{Kind: mj.Peng, Tile: kind(t)}
+			e.Tile = kind(t)
+			e.Red = red(t*4 + copies[called])
+		} else {
+			e = Event{Kind: AddedKan, Seat: who, From: who, Tile: kind(t), Red: red(t*4 + unused),
+				Meld: mj.Meld{Kind: mj.Gang, Tile: kind(t)}}
+		}
+	case m&0x20 != 0:
+		e = Event{Kind: Nuki, Seat: who, From: who, Tile: mj.Tile{Suit: mj.Honour, Value: mj.North}}
+	default:
+		// kan: the index of the called tile, or of any tile of a closed kan
+		i := m >> 8
+		if i >= 136 {
+			return Event{}, fmt.Errorf("bad kan %d", m)
+		}
+		e.Tile, e.Red = kind(i/4), red(i)
+		e.Meld = mj.Meld{Kind: mj.Gang, Tile: e.Tile}
+		if m&3 == 0 {
+			e.Kind, e.Meld.Concealed, e.Red = ClosedKan, true, false
+		}
+	}
+	return e, nil
+}
@@ -6,11 +6,13 @@ const (
 	Wan
 	Honour
 	Flower
+	Joker
 	// Only 7 suits can be effectively encoded in the single byte representation.
 )
 
 // Suit is the suit of a Tile. The zero Suit is invalid. There are three basic suits,
-// Bamboo, Coin and Wan, as well as the Honour and Flower suits.
+// Bamboo, Coin and Wan, as well as the Honour and Flower suits. Jokers are in a suit
+// of their own.
 type Suit byte
 
 const (
@@ -26,15 +28,30 @@ const (
 	AnimalBase Value = 48
 )
 
+// JokerTile is the only valid tile in the Joker suit. In house rules that use them,
+// jokers may stand in for any tile in a peng or gang.
+var JokerTile = Tile{Suit: Joker, Value: 1}
+
+const (
+	Rooster Value = iota + AnimalBase
+	Centipede
+	Cat
+	Mouse
+)
+
 // The number of unique melding tiles in the game.
 const NumUniqueMeldingTiles = 3*9 + 7
 
+// The number of tiles in the basic Hong Kong tileset. See TileSet.Len for other tilesets.
 const NumTiles = 4*NumUniqueMeldingTiles + 8
 
 // Value is the face value of a Tile, including honours and bonuses. The zero Value is invalid.
 // Values 1-9 inclusive are used for the basic suits. East, South, West, North, Zhong, Fa and Ban
 // are only valid for the Honour suit. Values 32-39 inclusive are only valid for the Flower suit.
 // Value 32 is defined as FlowerBase. This defines the basic Hong Kong tileset.
+//
+// Values 48-51 inclusive (Rooster, Centipede, Cat and Mouse) are the animal tiles used in
+// Singapore play. They are also in the Flower suit and never meld.
 type Value byte
 
 // Tile is a single tile played in mahjong, comprising a Suit and a Value.
@@ -52,6 +69,7 @@ const (
 	uniTileBamboo1 = '🀐'
 	uniTileCoin1   = '🀙'
 	uniTileFlower1 = '🀢'
+	uniTileJoker   = '🀪'
 	// Extended flower tiles
 	uniTileRooster   = '🐓'
 	uniTileCentipede = '🐛'
@@ -76,7 +94,10 @@ func (t Tile) Valid() bool {
 	case Honour:
 		return East <= t.Value && t.Value <= Ban
 	case Flower:
-		return FlowerBase <= t.Value && t.Value < (FlowerBase+8)
+		return (FlowerBase <= t.Value && t.Value < (FlowerBase+8)) ||
+			(AnimalBase <= t.Value && t.Value <= Mouse)
+	case Joker:
+		return t.Value == 1
 	}
 
 	return false
@@ -115,8 +136,14 @@ func (t Tile) String() string {
 		base = uniTileEast
 		offset = t.Value - East
 	case Flower:
-		base = uniTileFlower1
-		offset = t.Value - FlowerBase
+		if t.IsAnimal() {
+			base = []rune{uniTileRooster, uniTileCentipede, uniTileCat, uniTileMouse}[t.Value-AnimalBase]
+		} else {
+			base = uniTileFlower1
+			offset = t.Value - FlowerBase
+		}
+	case Joker:
+		base = uniTileJoker
 	}
 
 	if uniUseVS16 {
@@ -141,14 +168,21 @@ func (t Tile) Marshal() byte {
 	return (byte(t.Suit) << 5) | (byte(t.Value) & 31)
 }
 
-// CanMeld returns true if the Tile may participate in melds.
+// CanMeld returns true if the Tile may participate in melds. Jokers and bonus tiles do
+// not.
 func (t Tile) CanMeld() bool {
-	return t.Valid() && t.Suit != Flower
+	return t.Valid() && t.Suit != Flower && t.Suit != Joker
+}
+
+// IsBonus returns true if the Tile is a flower, season or animal, which is set aside
+// when drawn instead of being kept in the hand.
+func (t Tile) IsBonus() bool {
+	return t.Valid() && t.Suit == Flower
 }
 
 // IsBasic returns true if the Tile is a basic tile.
 func (t Tile) IsBasic() bool {
-	return t.Valid() && t.Suit != Flower && t.Suit != Honour
+	return t.Valid() && t.Suit != Flower && t.Suit != Honour && t.Suit != Joker
 }
 
 // IsTerminal returns true if the Tile is a basic tile and the value is 1 or 9.
@@ -156,6 +190,16 @@ func (t Tile) IsTerminal() bool {
 	return t.IsBasic() && (t.Value == 1 || t.Value == 9)
 }
 
+// IsAnimal returns true if the Tile is one of the four animal tiles.
+func (t Tile) IsAnimal() bool {
+	return t.Valid() && t.Suit == Flower && t.Value >= AnimalBase
+}
+
+// IsJoker returns true if the Tile is a joker.
+func (t Tile) IsJoker() bool {
+	return t == JokerTile
+}
+
 func (t Tile) Less(t2 Tile) bool {
 	if t.Suit != t2.Suit {
 		return t.Suit < t2.Suit
@@ -0,0 +1,21 @@
+package mj
+
+import "testing"
+
+func TestTile_CanMeld(t *testing.T) {
+	for _, c := range []struct {
+		tile           string
+		canMeld, bonus bool
+	}{
+		{"b1", true, false},
+		{"hz", true, false},
+		{"f1", false, true},
+		{"j1", false, false},
+	} {
+		tile := MustParseHand(c.tile)[0]
+		if tile.CanMeld() != c.canMeld || tile.IsBonus() != c.bonus {
+			t.Errorf("%s: CanMeld() = %t, IsBonus() = %t, want %t, %t",
+				c.tile, tile.CanMeld(), tile.IsBonus(), c.canMeld, c.bonus)
+		}
+	}
+}
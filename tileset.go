@@ -0,0 +1,178 @@
+package mj
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TileSet describes which tiles are used in a game and how many copies there are of each.
+// The zero TileSet is the basic Hong Kong tileset: four of each melding tile, and one of
+// each flower and season.
+type TileSet struct {
+	// NoFlowers and NoSeasons remove the flowers (values FlowerBase to FlowerBase+3) and
+	// the seasons (FlowerBase+4 to FlowerBase+7).
+	NoFlowers, NoSeasons bool
+	// Animals adds one of each animal tile, as in Singapore play.
+	Animals bool
+	// Jokers is the number of jokers.
+	Jokers int
+	// RedFives is the number of copies of each basic 5 that are red. It is only
+	// informational: red fives are not distinct Tiles, so this changes neither Count nor
+	// CheckHand. Code that scores red fives must track them itself, like score.Win's
+	// RedFives field.
+	RedFives int
+	// Removed lists the tiles that are taken out of the game entirely.
+	Removed Hand
+}
+
+var (
+	// baseTiles holds one of each tile that may be in a TileSet, in sorted order.
+	baseTiles = func() Hand {
+		var h Hand
+		for _, s := range []Suit{Bamboo, Coin, Wan} {
+			for v := Value(1); v <= 9; v++ {
+				h = append(h, Tile{s, v})
+			}
+		}
+		for v := East; v <= Ban; v++ {
+			h = append(h, Tile{Honour, v})
+		}
+		for v := FlowerBase; v < FlowerBase+8; v++ {
+			h = append(h, Tile{Flower, v})
+		}
+		for v := Rooster; v <= Mouse; v++ {
+			h = append(h, Tile{Flower, v})
+		}
+		h = append(h, JokerTile)
+		return h
+	}()
+
+	// hongKongCounts caches the counts of the zero TileSet, which is by far the most
+	// common one.
+	hongKongCounts = TileSet{}.counts()
+)
+
+// IsZero returns true if ts is the zero TileSet.
+func (ts TileSet) IsZero() bool {
+	return !ts.NoFlowers && !ts.NoSeasons && !ts.Animals && ts.Jokers == 0 && ts.RedFives == 0 &&
+		len(ts.Removed) == 0
+}
+
+// Count returns the number of copies of the tile in the set.
+func (ts TileSet) Count(t Tile) int {
+	if !t.Valid() {
+		return 0
+	}
+	for _, rt := range ts.Removed {
+		if rt == t {
+			return 0
+		}
+	}
+
+	switch {
+	case t.IsJoker():
+		return ts.Jokers
+	case t.CanMeld():
+		return 4
+	case t.IsAnimal():
+		if ts.Animals {
+			return 1
+		}
+	case t.Value < FlowerBase+4:
+		if !ts.NoFlowers {
+			return 1
+		}
+	default:
+		if !ts.NoSeasons {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Contains returns true if there is at least one copy of the tile in the set.
+func (ts TileSet) Contains(t Tile) bool {
+	return ts.Count(t) > 0
+}
+
+// Unique returns one of each tile in the set, in sorted order.
+func (ts TileSet) Unique() Hand {
+	h := make(Hand, 0, len(baseTiles))
+	for _, t := range baseTiles {
+		if ts.Contains(t) {
+			h = append(h, t)
+		}
+	}
+	return h
+}
+
+// AllTiles returns every copy of every tile in the set, in sorted order.
+func (ts TileSet) AllTiles() Hand {
+	h := make(Hand, 0, NumTiles)
+	for _, t := range baseTiles {
+		for i := ts.Count(t); i > 0; i-- {
+			h = append(h, t)
+		}
+	}
+	return h
+}
+
+// Len returns the number of tiles in the set. For the zero TileSet, this is NumTiles.
+func (ts TileSet) Len() int {
+	n := 0
+	for _, t := range baseTiles {
+		n += ts.Count(t)
+	}
+	return n
+}
+
+// NewCounter creates a new Counter with every tile in the set.
+func (ts TileSet) NewCounter() Counter {
+	var m map[Tile]int
+	if ts.hongKong() {
+		m = hongKongCounts
+	} else {
+		m = ts.counts()
+	}
+
+	c := Counter{m: make(map[Tile]int, len(m))}
+	for t, n := range m {
+		c.m[t] = n
+		c.n += n
+	}
+	return c
+}
+
+// CheckHand returns an error if the hand has a tile that is not in the set, or more
+// copies of a tile than the set has.
+func (ts TileSet) CheckHand(h Hand) error {
+	cnt := h.ToCount()
+	es := cnt.Entries()
+	sort.Slice(es, func(i, j int) bool { return es[i].Tile.Less(es[j].Tile) })
+
+	for _, e := range es {
+		if n := ts.Count(e.Tile); int(e.Count) > n {
+			if n == 0 {
+				return fmt.Errorf("tile %s is not in the tileset", e.Tile)
+			}
+			return fmt.Errorf("%d copies of tile %s, only %d in the tileset", e.Count, e.Tile, n)
+		}
+	}
+	return nil
+}
+
+func (ts TileSet) counts() map[Tile]int {
+	m := make(map[Tile]int, len(baseTiles))
+	for _, t := range baseTiles {
+		if n := ts.Count(t); n > 0 {
+			m[t] = n
+		}
+	}
+	return m
+}
+
+// hongKong returns true if the set has the same tiles as the zero TileSet.
+func (ts TileSet) hongKong() bool {
+	return !ts.NoFlowers && !ts.NoSeasons && !ts.Animals && ts.Jokers == 0 &&
+		len(ts.Removed) == 0
+}
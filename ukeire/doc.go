@@ -0,0 +1,3 @@
+// Package ukeire measures tile acceptance: the tiles that would bring a hand closer to
+// winning, and how many copies of them are left. It is used to recommend a discard.
+package ukeire
@@ -0,0 +1,93 @@
+package ukeire
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+)
+
+// Acceptance is the tile acceptance of a hand with 3n+1 tiles.
+type Acceptance struct {
+	// Shanten is the shanten of the hand, as returned by handcheck.Shanten.
+	Shanten int
+	// Tiles holds the tiles that would lower the shanten if drawn, in sorted order.
+	// A tile is included even if every copy outside the hand has been seen, but not if
+	// every copy is already in the hand.
+	Tiles mj.Hand
+	// Count is the number of unseen copies of Tiles.
+	Count int
+}
+
+// Discard is the acceptance after discarding a tile from a hand with 3n+2 tiles.
+type Discard struct {
+	Tile mj.Tile
+	Acceptance
+}
+
+// Accept returns the acceptance of a hand with 3n+1 concealed tiles. The visible
+// Counter holds the tiles seen outside the hand, such as discards and other players'
+// melds, and may be the zero Counter.
+func Accept(hand mj.Hand, visible mj.Counter, rs mj.Ruleset) Acceptance {
+	if len(hand)%3 != 1 {
+		panic(fmt.Sprintf("accept: hand of %d tiles", len(hand)))
+	}
+	return accept(hand, hand.ToCount(), visible, rs)
+}
+
+// Discards returns the acceptance after each distinct discard from a hand with 3n+2
+// concealed tiles. The best discard is first: the one with the lowest shanten, then
+// the most unseen accepted tiles, then the most distinct accepted tiles. Discards that
+// are equally good are kept in tile order. The visible Counter is as for Accept; the
+// discarded tile is counted as seen.
+func Discards(hand mj.Hand, visible mj.Counter, rs mj.Ruleset) []Discard {
+	if len(hand)%3 != 2 {
+		panic(fmt.Sprintf("discards: hand of %d tiles", len(hand)))
+	}
+
+	cnt := hand.ToCount()
+	sorted := cnt.ToHand(true)
+	var out []Discard
+	for i, t := range sorted {
+		if i > 0 && sorted[i-1] == t {
+			continue
+		}
+		out = append(out, Discard{Tile: t, Acceptance: accept(sorted.Remove(i), cnt, visible, rs)})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i].Acceptance, out[j].Acceptance
+		switch {
+		case a.Shanten != b.Shanten:
+			return a.Shanten < b.Shanten
+		case a.Count != b.Count:
+			return a.Count > b.Count
+		}
+		return len(a.Tiles) > len(b.Tiles)
+	})
+	return out
+}
+
+// accept works out the acceptance of hand. Copies of tiles in held are not available.
+func accept(hand mj.Hand, held mj.Counter, visible mj.Counter, rs mj.Ruleset) Acceptance {
+	a := Acceptance{Shanten: handcheck.Shanten(hand, rs)}
+	for _, t := range rs.Tiles.Unique() {
+		if !t.CanMeld() {
+			continue
+		}
+		unseen := rs.Tiles.Count(t) - held.Get(t)
+		if unseen <= 0 {
+			continue
+		}
+		if handcheck.Shanten(hand.Append(t), rs) >= a.Shanten {
+			continue
+		}
+
+		a.Tiles = append(a.Tiles, t)
+		if unseen -= visible.Get(t); unseen > 0 {
+			a.Count += unseen
+		}
+	}
+	return a
+}
@@ -0,0 +1,167 @@
+package ukeire
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+	"github.com/nik0sc/mj/special"
+	"github.com/nik0sc/mj/wait"
+)
+
+func TestAccept(t *testing.T) {
+	tests := []struct {
+		name    string
+		hand    string
+		visible string
+		want    Acceptance
+	}{
+		{
+			"two-sided",
+			"b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b4 b5",
+			"",
+			Acceptance{Shanten: 0, Tiles: mj.MustParseHand("b3 b6"), Count: 7},
+		},
+		{
+			"seen",
+			"b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b4 b5",
+			"b6 b6 b6 b6",
+			Acceptance{Shanten: 0, Tiles: mj.MustParseHand("b3 b6"), Count: 3},
+		},
+		{
+			"one away",
+			"b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b4 hb",
+			"",
+			Acceptance{Shanten: 1, Tiles: mj.MustParseHand("b1 b2 b3 b4 b5 b6 hz hb"), Count: 3 + 3 + 3 + 3 + 4 + 4 + 2 + 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var visible mj.Counter
+			if tt.visible != "" {
+				visible = mj.MustParseHand(tt.visible).ToCount()
+			}
+			got := Accept(mj.MustParseHand(tt.hand), visible, mj.HongKong)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Accept() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscards(t *testing.T) {
+	got := Discards(mj.MustParseHand("b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b4 b5 hb"), mj.Counter{}, mj.HongKong)
+	if len(got) != 13 {
+		t.Fatalf("got %d discards, want 13", len(got))
+	}
+	want := Discard{
+		Tile:       mj.Tile{Suit: mj.Honour, Value: mj.Ban},
+		Acceptance: Acceptance{Shanten: 0, Tiles: mj.MustParseHand("b3 b6"), Count: 7},
+	}
+	if !reflect.DeepEqual(got[0], want) {
+		t.Errorf("best discard = %+v, want %+v", got[0], want)
+	}
+	for _, d := range got[1:] {
+		if d.Shanten < got[0].Shanten {
+			t.Errorf("discard %s has lower shanten than the best", d.Tile)
+		}
+	}
+}
+
+// tenpaiHand builds a random winning hand of four sets and a pair, then takes one tile
+// out of it.
+func tenpaiHand(rng *rand.Rand) mj.Hand {
+	tiles := mj.HongKong.Tiles.Unique()
+	var melding mj.Hand
+	for _, t := range tiles {
+		if t.CanMeld() {
+			melding = append(melding, t)
+		}
+	}
+
+	cnt := make(map[mj.Tile]int)
+	var hand mj.Hand
+	add := func(ts ...mj.Tile) bool {
+		for _, t := range ts {
+			cnt[t]++
+		}
+		for _, t := range ts {
+			if cnt[t] > 4 {
+				for _, u := range ts {
+					cnt[u]--
+				}
+				return false
+			}
+		}
+		hand = append(hand, ts...)
+		return true
+	}
+	for sets := 0; sets < 4; {
+		t := melding[rng.Intn(len(melding))]
+		if t.IsBasic() && t.Value <= 7 && rng.Intn(2) == 0 {
+			next, after := mj.Tile{Suit: t.Suit, Value: t.Value + 1}, mj.Tile{Suit: t.Suit, Value: t.Value + 2}
+			if add(t, next, after) {
+				sets++
+			}
+		} else if add(t, t, t) {
+			sets++
+		}
+	}
+	for {
+		t := melding[rng.Intn(len(melding))]
+		if add(t, t) {
+			break
+		}
+	}
+	return hand.Remove(rng.Intn(len(hand)))
+}
+
+func TestAccept_MatchesWaits(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	rs := mj.HongKong
+	for i := 0; i < 200; i++ {
+		hand := tenpaiHand(rng)
+		if i%2 == 1 {
+			// a random hand is rarely waiting, which checks the other direction
+			hand = nil
+			for _, tile := range rs.Wall(rng) {
+				if tile.CanMeld() {
+					hand = append(hand, tile)
+				}
+				if len(hand) == 13 {
+					break
+				}
+			}
+		}
+
+		a := Accept(hand, mj.Counter{}, rs)
+		accepted := make(map[mj.Tile]bool)
+		for _, tile := range a.Tiles {
+			accepted[tile] = true
+		}
+
+		// the waits of any one grouping are among the accepted tiles
+		g := handcheck.OptCountChecker{UseMemo: true}.Check(hand)
+		for _, w := range wait.FindWithRules(g, true, rs) {
+			if a.Shanten != 0 || !accepted[w] {
+				t.Errorf("%s: wait %s not accepted by %+v", hand, w, a)
+			}
+		}
+
+		// and the waits over every grouping are exactly the accepted tiles
+		waits := make(map[mj.Tile]bool)
+		for _, w := range wait.Classify(hand, rs) {
+			waits[w.Tile] = true
+		}
+		for _, w := range special.Waits(hand, rs) {
+			waits[w] = true
+		}
+		if tenpai := len(waits) > 0; tenpai != (a.Shanten == 0) {
+			t.Errorf("%s: waits %v but shanten %d", hand, waits, a.Shanten)
+		} else if tenpai && !reflect.DeepEqual(waits, accepted) {
+			t.Errorf("%s: waits %v, accepted %v", hand, waits, accepted)
+		}
+	}
+}
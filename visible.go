@@ -0,0 +1,125 @@
+package mj
+
+// Visible tracks the tiles that one player can see during a game: the discards, declared
+// melds and bonus tiles of every seat, and the player's own hand. It reports how many
+// copies of a tile have not been seen, which are the copies that may still be drawn or
+// discarded by another player.
+// The zero value is ready to use, and tracks a game with the basic Hong Kong tileset.
+type Visible struct {
+	// Rules gives the tileset in play.
+	Rules Ruleset
+
+	public map[Tile]int
+	hand   Hand
+}
+
+// Discard records a tile discarded by any seat, including the player's own.
+func (v *Visible) Discard(t Tile) {
+	v.see(t)
+}
+
+// Meld records a meld declared by any seat. If the meld was formed by claiming a discard
+// that was already recorded, claimed is that tile, and it is not counted again.
+// Otherwise, claimed should be the zero Tile. All four tiles of a concealed gang are
+// counted, because the face up tiles show what the others are.
+func (v *Visible) Meld(m Meld, claimed Tile) {
+	for _, t := range m.Tiles() {
+		if t == claimed {
+			claimed = Tile{}
+			continue
+		}
+		v.see(t)
+	}
+}
+
+// Bonus records a flower, season or animal set aside by any seat.
+func (v *Visible) Bonus(t Tile) {
+	v.see(t)
+}
+
+// SetHand sets the concealed tiles in the player's own hand, replacing the hand that was
+// previously set. Tiles that leave the hand are only counted again if they are recorded
+// as a discard or in a meld.
+func (v *Visible) SetHand(h Hand) {
+	v.hand = make(Hand, len(h))
+	copy(v.hand, h)
+}
+
+// Seen returns a Counter of every tile the player has seen, including the player's own
+// hand.
+func (v *Visible) Seen() Counter {
+	m := make(map[Tile]int, len(v.public)+len(v.hand))
+	for t, n := range v.public {
+		m[t] += n
+	}
+	for _, t := range v.hand {
+		m[t]++
+	}
+	c, err := NewCounter(m)
+	if err != nil {
+		panic("Visible has invalid tiles: " + err.Error())
+	}
+	return c
+}
+
+// Public returns a Counter of the tiles seen outside the player's own hand. This is the
+// visible Counter expected by functions that already account for the hand, like
+// special.ThirteenOrphansWaits.
+func (v *Visible) Public() Counter {
+	c, err := NewCounter(v.public)
+	if err != nil {
+		panic("Visible has invalid tiles: " + err.Error())
+	}
+	return c
+}
+
+// Unseen returns the number of copies of the tile that the player has not seen. It is
+// never negative, even if more copies were recorded than the tileset has.
+func (v *Visible) Unseen(t Tile) int {
+	n := v.Rules.Tiles.Count(t) - v.public[t]
+	for _, t2 := range v.hand {
+		if t2 == t {
+			n--
+		}
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Live returns the total number of unseen copies of the tiles, such as the tiles that a
+// hand is waiting on. Each distinct tile is only counted once.
+func (v *Visible) Live(ts []Tile) int {
+	n := 0
+	done := make(map[Tile]bool, len(ts))
+	for _, t := range ts {
+		if !done[t] {
+			done[t] = true
+			n += v.Unseen(t)
+		}
+	}
+	return n
+}
+
+// Remaining returns a Counter of every tile in the tileset that the player has not seen.
+func (v *Visible) Remaining() Counter {
+	m := make(map[Tile]int)
+	for _, t := range v.Rules.Tiles.Unique() {
+		if n := v.Unseen(t); n > 0 {
+			m[t] = n
+		}
+	}
+	c, err := NewCounter(m)
+	if err != nil {
+		panic("Visible has invalid tiles: " + err.Error())
+	}
+	return c
+}
+
+func (v *Visible) see(t Tile) {
+	if v.public == nil {
+		v.public = make(map[Tile]int)
+	}
+	v.public[t]++
+}
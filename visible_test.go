@@ -0,0 +1,87 @@
+package mj
+
+import "testing"
+
+func TestVisible(t *testing.T) {
+	var v Visible
+	b5, c3, hz := MustParseHand("b5")[0], MustParseHand("c3")[0], MustParseHand("hz")[0]
+
+	if n := v.Unseen(b5); n != 4 {
+		t.Errorf("Unseen(b5) = %d before anything is seen, want 4", n)
+	}
+
+	v.Discard(b5)
+	v.Discard(c3)
+	// a peng of a discarded tile only shows two more copies
+	v.Meld(Meld{Kind: Peng, Tile: b5}, b5)
+	// a chi of a discarded tile shows the other two tiles
+	v.Meld(Meld{Kind: Chi, Tile: MustParseHand("c2")[0]}, c3)
+	// a concealed gang shows all four
+	v.Meld(Meld{Kind: Gang, Tile: hz, Concealed: true}, Tile{})
+	v.Bonus(MustParseHand("f1")[0])
+
+	for _, c := range []struct {
+		tile string
+		want int
+	}{
+		{"b5", 1},
+		{"c2", 3},
+		{"c3", 3},
+		{"c4", 3},
+		{"hz", 0},
+		{"f1", 0},
+		{"f2", 1},
+		{"w1", 4},
+	} {
+		if n := v.Unseen(MustParseHand(c.tile)[0]); n != c.want {
+			t.Errorf("Unseen(%s) = %d, want %d", c.tile, n, c.want)
+		}
+	}
+
+	v.SetHand(MustParseHand("b5 w1 w1"))
+	if n := v.Unseen(b5); n != 0 {
+		t.Errorf("Unseen(b5) = %d with the last copy in hand, want 0", n)
+	}
+	if n := v.Live(MustParseHand("w1 w1 w2 b5")); n != 2+4 {
+		t.Errorf("Live() = %d, want 6", n)
+	}
+
+	// discarding from the hand is counted once the discard is recorded
+	v.SetHand(MustParseHand("w1 w1"))
+	if n := v.Unseen(b5); n != 1 {
+		t.Errorf("Unseen(b5) = %d after the hand changed, want 1", n)
+	}
+	v.Discard(b5)
+	if n := v.Unseen(b5); n != 0 {
+		t.Errorf("Unseen(b5) = %d after discarding it, want 0", n)
+	}
+
+	pub := v.Public()
+	if pub.Get(b5) != 4 || pub.Get(c3) != 1 || pub.Get(hz) != 4 || pub.Get(MustParseHand("w1")[0]) != 0 {
+		t.Errorf("Public() = %v", pub)
+	}
+	seen := v.Seen()
+	if seen.Get(MustParseHand("w1")[0]) != 2 || seen.Len() != pub.Len()+2 {
+		t.Errorf("Seen() = %v", seen)
+	}
+	rem := v.Remaining()
+	if rem.Get(b5) != 0 || rem.Get(MustParseHand("w1")[0]) != 2 || rem.Len()+seen.Len() != v.Rules.Tiles.NewCounter().Len() {
+		t.Errorf("Remaining() = %v", rem)
+	}
+}
+
+func TestVisible_Unseen(t *testing.T) {
+	// more copies than the tileset has never gives a negative count
+	v := Visible{Rules: Riichi}
+	hb := MustParseHand("hb")[0]
+	for i := 0; i < 5; i++ {
+		v.Discard(hb)
+	}
+	if n := v.Unseen(hb); n != 0 {
+		t.Errorf("Unseen(hb) = %d, want 0", n)
+	}
+	// tiles that are not in the tileset are never unseen
+	if n := v.Unseen(MustParseHand("f1")[0]); n != 0 {
+		t.Errorf("Unseen(f1) = %d without flowers, want 0", n)
+	}
+}
@@ -0,0 +1,134 @@
+package wait
+
+import (
+	"sort"
+
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+)
+
+const (
+	// TwoSided is a wait on either end of two consecutive tiles, like 4-5 waiting on 3 or 6.
+	TwoSided Shape = iota + 1
+	// Edge is a wait on one end of 1-2 or 8-9.
+	Edge
+	// Closed is a wait on the middle tile of a chi, like 4-6 waiting on 5.
+	Closed
+	// DualPung is a wait on either of two pairs to become a peng.
+	DualPung
+	// Single is a wait on the pair.
+	Single
+)
+
+// Shape is the shape of the partial set that a waited tile completes.
+type Shape byte
+
+var shapeNames = map[Shape]string{
+	TwoSided: "two-sided",
+	Edge:     "edge",
+	Closed:   "closed",
+	DualPung: "dual pung",
+	Single:   "single",
+}
+
+func (s Shape) String() string {
+	if n, ok := shapeNames[s]; ok {
+		return n
+	}
+	return "unknown"
+}
+
+// ChiShape returns the shape of the wait that a winning tile completes in the chi
+// starting at first.
+func ChiShape(first, win mj.Tile) Shape {
+	switch {
+	case win.Value == first.Value+1:
+		return Closed
+	case win.Value == first.Value+2 && first.Value == 1:
+		return Edge
+	case win.Value == first.Value && first.Value == 7:
+		return Edge
+	}
+	return TwoSided
+}
+
+// Wait is a waited tile, annotated with its shape.
+type Wait struct {
+	Tile  mj.Tile
+	Shape Shape
+	// Group is the grouping of the waiting hand that produces the wait. The partial set
+	// is in Free for a chi or pair wait, or in Pairs for a dual pung wait.
+	Group mj.Group
+}
+
+// Classify finds the waits of a waiting hand for every way of grouping it. The same
+// tile may be returned more than once, with different shapes or groupings. The hand
+// should hold the concealed tiles only, and the number of sets is not checked against
+// the Ruleset, so declared melds may be left out. Tiles that are all in the hand are
+// not waited on, and special hands are not considered.
+// Waits are sorted by tile, then by shape.
+func Classify(hand mj.Hand, rs mj.Ruleset) []Wait {
+	var out []Wait
+	seen := make(map[string]bool)
+	add := func(w Wait) {
+		key := w.Tile.String() + string(rune('0'+w.Shape)) + w.Group.Marshal()
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, w)
+		}
+	}
+
+	cnt := hand.ToCount()
+	for _, t := range rs.Tiles.Unique() {
+		if !t.CanMeld() || cnt.Get(t) >= rs.Tiles.Count(t) {
+			continue
+		}
+
+		for _, g := range handcheck.CompleteWithRules(hand.Append(t), rs) {
+			if g.Pairs[0] == t {
+				add(Wait{Tile: t, Shape: Single, Group: mj.Group{
+					Pengs: g.Pengs,
+					Chis:  g.Chis,
+					Free:  mj.Hand{t},
+				}})
+			}
+			for i, p := range g.Pengs {
+				if p != t {
+					continue
+				}
+				pairs := mj.Hand{g.Pairs[0], t}
+				sort.Sort(pairs)
+				add(Wait{Tile: t, Shape: DualPung, Group: mj.Group{
+					Pengs: g.Pengs.Remove(i),
+					Chis:  g.Chis,
+					Pairs: pairs,
+				}})
+			}
+			for i, c := range g.Chis {
+				if c.Suit != t.Suit || t.Value < c.Value || t.Value > c.Value+2 {
+					continue
+				}
+				var free mj.Hand
+				for v := c.Value; v <= c.Value+2; v++ {
+					if v != t.Value {
+						free = append(free, mj.Tile{Suit: c.Suit, Value: v})
+					}
+				}
+				add(Wait{Tile: t, Shape: ChiShape(c, t), Group: mj.Group{
+					Pengs: g.Pengs,
+					Chis:  g.Chis.Remove(i),
+					Pairs: g.Pairs,
+					Free:  free,
+				}})
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Tile != out[j].Tile {
+			return out[i].Tile.Less(out[j].Tile)
+		}
+		return out[i].Shape < out[j].Shape
+	})
+	return out
+}
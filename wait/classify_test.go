@@ -0,0 +1,95 @@
+package wait
+
+import (
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func TestClassify(t *testing.T) {
+	type shaped struct {
+		tile  string
+		shape Shape
+	}
+	tests := []struct {
+		name string
+		hand string
+		rs   mj.Ruleset
+		want []shaped
+	}{
+		{
+			"two-sided",
+			"b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz b4 b5",
+			mj.HongKong,
+			// b3 could also complete b1 b2 with b3 b4 b5 as a chi
+			[]shaped{{"b3", TwoSided}, {"b3", Edge}, {"b6", TwoSided}},
+		},
+		{
+			"edge",
+			"b4 b5 b6 c4 c5 c6 w7 w8 w9 hz hz b1 b2",
+			mj.HongKong,
+			[]shaped{{"b3", Edge}},
+		},
+		{
+			"closed",
+			"b4 b5 b6 c4 c5 c6 w7 w8 w9 hz hz b1 b3",
+			mj.HongKong,
+			[]shaped{{"b2", Closed}},
+		},
+		{
+			"dual pung",
+			"b4 b5 b6 c4 c5 c6 w7 w8 w9 hz hz b1 b1",
+			mj.HongKong,
+			[]shaped{{"b1", DualPung}, {"hz", DualPung}},
+		},
+		{
+			"single",
+			"b4 b5 b6 c4 c5 c6 w7 w8 w9 hz hz hz b1",
+			mj.HongKong,
+			[]shaped{{"b1", Single}},
+		},
+		{
+			"several groupings",
+			// b2 b3 b4 with b5 single, or b3 b4 b5 with b2 single
+			"b2 b3 b4 b5 c4 c5 c6 w7 w8 w9 hz hz hz",
+			mj.HongKong,
+			[]shaped{{"b2", Single}, {"b5", Single}},
+		},
+		{
+			"pair or two-sided",
+			"b1 b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz hz",
+			mj.HongKong,
+			[]shaped{{"b1", TwoSided}, {"b1", Single}, {"b4", TwoSided}},
+		},
+		{
+			"no chi",
+			"b1 b1 b1 c4 c4 c4 w7 w7 w7 hz hz b4 b5",
+			mj.Sanma,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Classify(mj.MustParseHand(tt.hand), tt.rs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d waits, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, w := range tt.want {
+				if got[i].Tile != mj.MustParseHand(w.tile)[0] || got[i].Shape != w.shape {
+					t.Errorf("wait %d: got %s %s, want %s %s", i, got[i].Tile, got[i].Shape, w.tile, w.shape)
+				}
+			}
+		})
+	}
+}
+
+func TestClassify_Group(t *testing.T) {
+	got := Classify(mj.MustParseHand("b4 b5 b6 c4 c5 c6 w7 w8 w9 hz hz b1 b1"), mj.HongKong)
+	if len(got) != 2 {
+		t.Fatalf("got %d waits, want 2", len(got))
+	}
+	g := got[0].Group
+	if g.Pairs.String() != mj.MustParseHand("b1 hz").String() || len(g.Chis) != 3 || len(g.Pengs) != 0 {
+		t.Errorf("unexpected group: %v", g)
+	}
+}
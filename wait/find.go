@@ -9,28 +9,43 @@ import (
 
 // Find takes an input mj.Group and determines what tiles
 // the player could wait for to win. Tile counts within the hand are
-// considered, but there is no consideration of discarded tile counts.
+// considered, but there is no consideration of discarded tile counts;
+// use FindLive for that.
 // Find will not propose waits for special hands like thirteen orphans, all
 // pairs, etc.
 //
 // If allowMiddle is false, the middle tile of a chi will not be proposed. This
 // is useful for excluding middle tiles in some pinghu situations.
+//
+// The hand is assumed to be a 13-tile hand. Use FindWithRules for other hand sizes.
 func Find(result mj.Group, allowMiddle bool) []mj.Tile {
+	return FindWithRules(result, allowMiddle, mj.HongKong)
+}
+
+// FindWithRules is like Find, but the number of sets in a winning hand is taken from
+// the Ruleset, chi waits are not proposed if the Ruleset forbids chis, and tiles are
+// only proposed while the Ruleset's tileset has copies that are not in the hand. Declared
+// melds should be included in the mj.Group as pengs or chis.
+func FindWithRules(result mj.Group, allowMiddle bool, rs mj.Ruleset) []mj.Tile {
 	meldsets := result.Chis.Len() + result.Pengs.Len()
+	sets := rs.Sets()
 	cnt := result.ToCount()
 	var waits []mj.Tile
 
-	if result.Free.Len() == 0 && meldsets == 3 && result.Pairs.Len() == 2 {
+	if result.Free.Len() == 0 && meldsets == sets-1 && result.Pairs.Len() == 2 {
 		// check for peng from pairs
 		// Either pair could be possible
 		for _, t := range result.Pairs {
-			if cnt.Get(t) < 4 && t.CanMeld() {
+			if cnt.Get(t) < rs.Tiles.Count(t) && t.CanMeld() {
 				waits = append(waits, t)
 			}
 		}
 		return waits
-	} else if result.Free.Len() == 2 && meldsets == 3 && result.Pairs.Len() == 1 {
+	} else if result.Free.Len() == 2 && meldsets == sets-1 && result.Pairs.Len() == 1 {
 		// check for chi
+		if rs.NoChi {
+			return nil
+		}
 		if !result.Free[0].IsBasic() || !result.Free[1].IsBasic() {
 			return nil
 		}
@@ -50,7 +65,7 @@ func Find(result mj.Group, allowMiddle bool) []mj.Tile {
 				if !t.Valid() {
 					panic(fmt.Sprintf("invalid low tile: %+v", t))
 				}
-				if cnt.Get(t) < 4 {
+				if cnt.Get(t) < rs.Tiles.Count(t) {
 					waits = append(waits, t)
 				}
 			}
@@ -60,7 +75,7 @@ func Find(result mj.Group, allowMiddle bool) []mj.Tile {
 				if !t.Valid() {
 					panic(fmt.Sprintf("invalid high tile: %+v", t))
 				}
-				if cnt.Get(t) < 4 {
+				if cnt.Get(t) < rs.Tiles.Count(t) {
 					waits = append(waits, t)
 				}
 			}
@@ -72,16 +87,16 @@ func Find(result mj.Group, allowMiddle bool) []mj.Tile {
 			if !t.Valid() {
 				panic(fmt.Sprintf("invalid middle tile: %+v", t))
 			}
-			if cnt.Get(t) < 4 {
+			if cnt.Get(t) < rs.Tiles.Count(t) {
 				waits = append(waits, t)
 			}
 			return waits
 		}
 		return nil
-	} else if result.Free.Len() == 1 && meldsets == 4 && result.Pairs.Len() == 0 {
+	} else if result.Free.Len() == 1 && meldsets == sets && result.Pairs.Len() == 0 {
 		// check for pair
 		t := result.Free[0]
-		if cnt.Get(t) < 4 && t.CanMeld() {
+		if cnt.Get(t) < rs.Tiles.Count(t) && t.CanMeld() {
 			waits = append(waits, t)
 		}
 		return waits
@@ -89,3 +104,18 @@ func Find(result mj.Group, allowMiddle bool) []mj.Tile {
 		return nil
 	}
 }
+
+// FindLive is like FindWithRules, but also drops the waits that the player has seen
+// every copy of, in the hand, the discards or any meld. The Visible gives the Ruleset,
+// and should have the player's concealed tiles set with SetHand. Use Visible.Live to
+// count the unseen copies of the waits.
+func FindLive(result mj.Group, allowMiddle bool, v *mj.Visible) []mj.Tile {
+	waits := FindWithRules(result, allowMiddle, v.Rules)
+	var live []mj.Tile
+	for _, t := range waits {
+		if v.Unseen(t) > 0 {
+			live = append(live, t)
+		}
+	}
+	return live
+}
@@ -175,3 +175,107 @@ func Test_Find(t *testing.T) {
 		})
 	}
 }
+
+func Test_FindWithRules(t *testing.T) {
+	tests := []struct {
+		name string
+		res  mj.Group
+		rs   mj.Ruleset
+		want []mj.Tile
+	}{
+		{
+			"taiwanese pair",
+			mj.Group{
+				Pengs: mj.MustParseHand("b2 b3 b4 b5 c1"),
+				Free:  mj.MustParseHand("b1"),
+			},
+			mj.Taiwanese,
+			mj.MustParseHand("b1"),
+		},
+		{
+			"taiwanese chi",
+			mj.Group{
+				Pengs: mj.MustParseHand("c1 c2 c3"),
+				Chis:  mj.MustParseHand("w1"),
+				Pairs: mj.MustParseHand("b5"),
+				Free:  mj.MustParseHand("b7 b8"),
+			},
+			mj.Taiwanese,
+			mj.MustParseHand("b6 b9"),
+		},
+		{
+			"removed tile",
+			mj.Group{
+				Pengs: mj.MustParseHand("c1 c2 c3"),
+				Pairs: mj.MustParseHand("b5"),
+				Free:  mj.MustParseHand("b7 b8"),
+			},
+			mj.Ruleset{Name: "no b6", Tiles: mj.TileSet{Removed: mj.MustParseHand("b6")}},
+			mj.MustParseHand("b9"),
+		},
+		{
+			"sanma chi",
+			mj.Group{
+				Pengs: mj.MustParseHand("c1 c2 c3"),
+				Pairs: mj.MustParseHand("b5"),
+				Free:  mj.MustParseHand("b7 b8"),
+			},
+			mj.Sanma,
+			[]mj.Tile{},
+		},
+		{
+			"sanma peng",
+			mj.Group{
+				Pengs: mj.MustParseHand("c1 c2 c3"),
+				Pairs: mj.MustParseHand("b5 b7"),
+			},
+			mj.Sanma,
+			mj.MustParseHand("b5 b7"),
+		},
+		{
+			"taiwanese short",
+			mj.Group{
+				Pengs: mj.MustParseHand("b2 b3 b4 b5"),
+				Free:  mj.MustParseHand("b1"),
+			},
+			mj.Taiwanese,
+			[]mj.Tile{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goth := mj.Hand(FindWithRules(tt.res, true, tt.rs))
+			wanth := mj.Hand(tt.want)
+			sort.Sort(goth)
+			sort.Sort(wanth)
+
+			if goth.Marshal() != wanth.Marshal() {
+				t.Fatalf("want %s, got %s", wanth.String(), goth.String())
+			}
+		})
+	}
+}
+
+func Test_FindLive(t *testing.T) {
+	// b1 b2 b3 c4 c5 c6 w7 w8 w9 b5 b5 b7 b8, waiting on b6 or b9
+	res := mj.Group{
+		Chis:  mj.MustParseHand("b1 c4 w7"),
+		Pairs: mj.MustParseHand("b5"),
+		Free:  mj.MustParseHand("b7 b8"),
+	}
+	b9 := mj.MustParseHand("b9")[0]
+	var v mj.Visible
+	v.SetHand(res.ToHand())
+	v.Discard(b9)
+	v.Discard(b9)
+	v.Meld(mj.Meld{Kind: mj.Peng, Tile: b9}, b9)
+
+	got := FindLive(res, true, &v)
+	if len(got) != 1 || got[0] != mj.MustParseHand("b6")[0] {
+		t.Errorf("FindLive() = %v, want b6", got)
+	}
+	if n := v.Live(got); n != 4 {
+		t.Errorf("Live() = %d, want 4", n)
+	}
+}
@@ -0,0 +1,36 @@
+package wait
+
+import (
+	"github.com/nik0sc/mj"
+	"github.com/nik0sc/mj/handcheck"
+)
+
+// FindWithJokers returns the tiles that would complete a hand that may contain jokers.
+// Unlike Find, it takes the hand itself, because the grouping of the hand depends on
+// the tile that is added. The hand should have as many tiles as the Ruleset's waiting
+// hand, and the waits are returned in sorted order.
+//
+// A joker can stand in for any tile in a peng, so a hand that is complete apart from
+// two jokers is won by any tile in play. A joker is also reported as a wait whenever
+// drawing one would complete the hand.
+func FindWithJokers(hand mj.Hand, rs mj.Ruleset) []mj.Tile {
+	cnt := hand.ToCount()
+	c := handcheck.JokerChecker{UseMemo: true, NoChi: rs.NoChi}
+
+	candidates := rs.Tiles.Unique()
+	if cnt.Get(mj.JokerTile) > 0 && !rs.Tiles.Contains(mj.JokerTile) {
+		candidates = candidates.Append(mj.JokerTile)
+	}
+
+	waits := []mj.Tile{}
+	for _, t := range candidates {
+		if !t.IsJoker() && (!t.CanMeld() || cnt.Get(t) >= rs.Tiles.Count(t)) {
+			continue
+		}
+		g := c.Check(hand.Append(t))
+		if len(g.Free) == 0 && len(g.Pairs) == 1 && len(g.Pengs)+len(g.Chis) == rs.Sets() {
+			waits = append(waits, t)
+		}
+	}
+	return waits
+}
@@ -0,0 +1,52 @@
+package wait
+
+import (
+	"testing"
+
+	"github.com/nik0sc/mj"
+)
+
+func Test_FindWithJokers(t *testing.T) {
+	rs := mj.Ruleset{Tiles: mj.TileSet{Jokers: 8}}
+	all := mj.MustParseHand(
+		"b1 b2 b3 b4 b5 b6 b7 b8 b9 c1 c2 c3 c4 c5 c6 c7 c8 c9 w1 w2 w3 w4 w5 w6 w7 w8 w9 " +
+			"he hs hw hn hz hf hb")
+
+	tests := []struct {
+		name string
+		hand string
+		want mj.Hand
+	}{
+		{
+			"no jokers",
+			"b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz hf hf",
+			mj.MustParseHand("hz hf j1"),
+		},
+		{
+			"joker fills the peng",
+			"b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz hf j1",
+			// hf hf j1 and a pair of hz, or hz hz j1 and a pair of hf
+			mj.MustParseHand("hf j1"),
+		},
+		{
+			"joker cannot pair",
+			"b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz hz j1",
+			// hz hz j1 and a pair of hz
+			mj.MustParseHand("hz j1"),
+		},
+		{
+			"any tile",
+			"b1 b2 b3 c4 c5 c6 w7 w8 w9 hz hz j1 j1",
+			// a third joker does not make a peng on its own
+			all,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mj.Hand(FindWithJokers(mj.MustParseHand(tt.hand), rs))
+			if got.Marshal() != tt.want.Marshal() {
+				t.Errorf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
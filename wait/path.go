@@ -8,6 +8,9 @@ import (
 )
 
 const (
+	// NumTilesInHand is the size of a waiting hand in the basic ruleset.
+	//
+	// Deprecated: use mj.Ruleset.Size, which also covers other hand sizes.
 	NumTilesInHand = 13
 )
 
@@ -26,7 +29,13 @@ type EditSequence []Edit
 // If `depth` > 0, at most `depth` number of edits is allowed (ie. limits
 // search depth).
 func FindPath(h mj.Hand, avail mj.Counter, depth int) (edits EditSequence, waits []mj.Tile, err error) {
-	if h.Len() != NumTilesInHand {
+	return FindPathWithRules(h, avail, depth, mj.HongKong)
+}
+
+// FindPathWithRules is like FindPath, but the hand size and whether chis are allowed
+// are taken from the Ruleset.
+func FindPathWithRules(h mj.Hand, avail mj.Counter, depth int, rs mj.Ruleset) (edits EditSequence, waits []mj.Tile, err error) {
+	if h.Len() != rs.Size() {
 		return nil, nil, errors.New("not enough tiles")
 	}
 
@@ -34,10 +43,10 @@ func FindPath(h mj.Hand, avail mj.Counter, depth int) (edits EditSequence, waits
 		return nil, nil, errors.New("failed validation")
 	}
 
-	c := handcheck.OptHandRLEChecker{UseMemo: true}
+	c := handcheck.OptHandRLEChecker{UseMemo: true, NoChi: rs.NoChi}
 	group := c.Check(h)
 
-	waits = Find(group, true)
+	waits = FindWithRules(group, true, rs)
 	if waits != nil {
 		return nil, waits, nil
 	}